@@ -84,7 +84,7 @@ func (d *MyAccountDataSource) Configure(ctx context.Context, req datasource.Conf
 
 	d.client = data.client
 	// TODO: precedence needed. only override if not set
-	d.address.Felt = data.address
+	d.address.FromFelt(data.address)
 	// d.publicKey = data.publicKey
 }
 
@@ -99,9 +99,17 @@ func (d *MyAccountDataSource) Read(ctx context.Context, req datasource.ReadReque
 	}
 
 	address := d.address.Felt
+	if address == nil {
+		resp.Diagnostics.AddError(
+			"No account configured",
+			"The provider is configured without an account address. "+
+				"Set address in the provider or the "+AccountAddressEnvVar+" environment variable.",
+		)
+		return
+	}
 
 	classHash, err := d.client.ClassHashAt(
-		context.Background(),
+		ctx,
 		rpc.WithBlockTag("latest"),
 		address,
 	)
@@ -115,7 +123,7 @@ func (d *MyAccountDataSource) Read(ctx context.Context, req datasource.ReadReque
 	}
 
 	data.Address = d.address
-	data.ClassHash.Felt = classHash
+	data.ClassHash.FromFelt(classHash)
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
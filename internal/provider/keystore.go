@@ -0,0 +1,173 @@
+package provider
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/curve"
+
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/crypto/scrypt"
+	"golang.org/x/crypto/sha3"
+)
+
+const KeystorePasswordEnvVar = "STARKNET_KEYSTORE_PASSWORD"
+
+// EncryptedKeystore describes a Web3 Secret Storage v3 file, the format used
+// by starkli and Argent keystores. Geth style "Crypto" key is matched too, as
+// JSON keys are decoded case-insensitively.
+type EncryptedKeystore struct {
+	Version int            `json:"version"`
+	Crypto  KeystoreCrypto `json:"crypto"`
+}
+
+type KeystoreCrypto struct {
+	Cipher       string `json:"cipher"`
+	CipherParams struct {
+		IV string `json:"iv"`
+	} `json:"cipherparams"`
+	CipherText string          `json:"ciphertext"`
+	KDF        string          `json:"kdf"`
+	KDFParams  json.RawMessage `json:"kdfparams"`
+	MAC        string          `json:"mac"`
+}
+
+type scryptParams struct {
+	DKLen int    `json:"dklen"`
+	N     int    `json:"n"`
+	R     int    `json:"r"`
+	P     int    `json:"p"`
+	Salt  string `json:"salt"`
+}
+
+type pbkdf2Params struct {
+	DKLen int    `json:"dklen"`
+	C     int    `json:"c"`
+	PRF   string `json:"prf"`
+	Salt  string `json:"salt"`
+}
+
+func (c KeystoreCrypto) deriveKey(password string) ([]byte, error) {
+	switch c.KDF {
+	case "scrypt":
+		var params scryptParams
+		if err := json.Unmarshal(c.KDFParams, &params); err != nil {
+			return nil, fmt.Errorf("invalid scrypt params: %w", err)
+		}
+		salt, err := hex.DecodeString(params.Salt)
+		if err != nil {
+			return nil, fmt.Errorf("invalid scrypt salt: %w", err)
+		}
+		return scrypt.Key([]byte(password), salt, params.N, params.R, params.P, params.DKLen)
+	case "pbkdf2":
+		var params pbkdf2Params
+		if err := json.Unmarshal(c.KDFParams, &params); err != nil {
+			return nil, fmt.Errorf("invalid pbkdf2 params: %w", err)
+		}
+		if params.PRF != "hmac-sha256" {
+			return nil, fmt.Errorf("unsupported pbkdf2 prf %q, expected hmac-sha256", params.PRF)
+		}
+		salt, err := hex.DecodeString(params.Salt)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pbkdf2 salt: %w", err)
+		}
+		return pbkdf2.Key([]byte(password), salt, params.C, params.DKLen, sha256.New), nil
+	}
+	return nil, fmt.Errorf("unsupported kdf %q, expected scrypt or pbkdf2", c.KDF)
+}
+
+// decrypt checks the MAC and returns the decrypted private key bytes.
+func (c KeystoreCrypto) decrypt(password string) ([]byte, error) {
+	if c.Cipher != "aes-128-ctr" {
+		return nil, fmt.Errorf("unsupported cipher %q, expected aes-128-ctr", c.Cipher)
+	}
+
+	cipherText, err := hex.DecodeString(c.CipherText)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ciphertext: %w", err)
+	}
+	iv, err := hex.DecodeString(c.CipherParams.IV)
+	if err != nil {
+		return nil, fmt.Errorf("invalid iv: %w", err)
+	}
+	if len(iv) != aes.BlockSize {
+		return nil, fmt.Errorf("invalid iv, expected %d bytes, got %d", aes.BlockSize, len(iv))
+	}
+	mac, err := hex.DecodeString(c.MAC)
+	if err != nil {
+		return nil, fmt.Errorf("invalid mac: %w", err)
+	}
+
+	derivedKey, err := c.deriveKey(password)
+	if err != nil {
+		return nil, err
+	}
+	if len(derivedKey) < 32 {
+		return nil, fmt.Errorf("derived key is too short, expected at least 32 bytes, got %d", len(derivedKey))
+	}
+
+	hash := sha3.NewLegacyKeccak256()
+	hash.Write(derivedKey[16:32])
+	hash.Write(cipherText)
+	if !bytes.Equal(hash.Sum(nil), mac) {
+		return nil, errors.New("mac mismatch, the password is probably wrong")
+	}
+
+	block, err := aes.NewCipher(derivedKey[:16])
+	if err != nil {
+		return nil, err
+	}
+	plainText := make([]byte, len(cipherText))
+	cipher.NewCTR(block, iv).XORKeyStream(plainText, cipherText)
+
+	// Stark private keys are felts, they fit 32 bytes
+	if len(plainText) == 0 || len(plainText) > 32 {
+		return nil, fmt.Errorf("invalid private key, expected 1 to 32 bytes, got %d", len(plainText))
+	}
+
+	return plainText, nil
+}
+
+// ReadEncryptedKeystore decrypts the keystore file and returns the private key.
+func ReadEncryptedKeystore(path string, password string) (*big.Int, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var keystore EncryptedKeystore
+	err = json.Unmarshal(content, &keystore)
+	if err != nil {
+		return nil, fmt.Errorf("can't parse keystore %s: %w", path, err)
+	}
+
+	if keystore.Version != 3 {
+		return nil, fmt.Errorf("unsupported keystore version %d, expected 3", keystore.Version)
+	}
+
+	privateKey, err := keystore.Crypto.decrypt(password)
+	if err != nil {
+		return nil, fmt.Errorf("can't decrypt keystore %s: %w", path, err)
+	}
+
+	return new(big.Int).SetBytes(privateKey), nil
+}
+
+// PublicKeyFromPrivateKey derives the Stark public key, the x coordinate of
+// the private key multiplied by the curve generator.
+func PublicKeyFromPrivateKey(privateKey *big.Int) (*felt.Felt, error) {
+	x, _, err := curve.Curve.PrivateToPoint(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	return new(felt.Felt).SetBigInt(x), nil
+}
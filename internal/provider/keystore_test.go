@@ -0,0 +1,211 @@
+package provider
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/scrypt"
+	"golang.org/x/crypto/sha3"
+)
+
+// Web3 Secret Storage v3 test vectors, also used by the geth keystore tests.
+const (
+	keystoreVectorPassword   = "testpassword"
+	keystoreVectorPrivateKey = "7a28b5ba57c53603b0b07b56bba752f7784bf506fa95edc395f5cf6c7514fe9d"
+
+	keystoreVectorScrypt = `{
+		"crypto": {
+			"cipher": "aes-128-ctr",
+			"cipherparams": {"iv": "83dbcc02d8ccb40e466191a123791e0e"},
+			"ciphertext": "d172bf743a674da9cdad04534d56926ef8358534d458fffccd4e6ad2fbde479c",
+			"kdf": "scrypt",
+			"kdfparams": {
+				"dklen": 32,
+				"n": 262144,
+				"r": 1,
+				"p": 8,
+				"salt": "ab0c7876052600dd703518d6fc3fe8984592145b591fc8fb5c6d43190334ba19"
+			},
+			"mac": "2103ac29920d71da29f15d75b4a16dbe95cfd7ff8faea1056c33131d846e3097"
+		},
+		"id": "3198bc9c-6672-5ab3-d995-4942343ae5b6",
+		"version": 3
+	}`
+
+	keystoreVectorPbkdf2 = `{
+		"crypto": {
+			"cipher": "aes-128-ctr",
+			"cipherparams": {"iv": "6087dab2f9fdbbfaddc31a909735c1e6"},
+			"ciphertext": "5318b4d5bcd28de64ee5559e671353e16f075ecae9f99c7a79a38af5f869aa46",
+			"kdf": "pbkdf2",
+			"kdfparams": {
+				"c": 262144,
+				"dklen": 32,
+				"prf": "hmac-sha256",
+				"salt": "ae3cd4e7013836a3df6bd7241b12db061dbe2c6785853cce422d148a624ce0bd"
+			},
+			"mac": "517ead924a9d0dc3124507e3393d175ce3ff7c1e96529c6c555ce9e51205e9b2"
+		},
+		"id": "3198bc9c-6672-5ab3-d995-4942343ae5b6",
+		"version": 3
+	}`
+
+	// Geth style capitalized "Crypto" key with a 31 byte private key and
+	// cheap scrypt parameters.
+	keystoreVectorShortKey = `{
+		"Crypto": {
+			"cipher": "aes-128-ctr",
+			"cipherparams": {"iv": "e0c41130a323adc1446fc82f724bca2f"},
+			"ciphertext": "9517cd5bdbe69076f9bf5057248c6c050141e970efa36ce53692d5d59a3984",
+			"kdf": "scrypt",
+			"kdfparams": {
+				"dklen": 32,
+				"n": 2,
+				"r": 8,
+				"p": 1,
+				"salt": "711f816911c92d649fb4c84b047915679933555030b3552c1212609b38208c63"
+			},
+			"mac": "d5e116151c6aa71470e67a7d42c9620c75c4d23229847dcc127794f0732b0db5"
+		},
+		"id": "fecfc4ce-e956-48fd-953b-30f8b52ed66c",
+		"version": 3
+	}`
+)
+
+// encryptTestKeystore encrypts privateKey with cheap scrypt parameters.
+func encryptTestKeystore(t *testing.T, password string, privateKey []byte, iv []byte) string {
+	t.Helper()
+
+	salt := make([]byte, 32)
+	derivedKey, err := scrypt.Key([]byte(password), salt, 2, 8, 1, 32)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	block, err := aes.NewCipher(derivedKey[:16])
+	if err != nil {
+		t.Fatal(err)
+	}
+	cipherText := make([]byte, len(privateKey))
+	cipher.NewCTR(block, iv).XORKeyStream(cipherText, privateKey)
+
+	hash := sha3.NewLegacyKeccak256()
+	hash.Write(derivedKey[16:32])
+	hash.Write(cipherText)
+
+	keystore := map[string]interface{}{
+		"version": 3,
+		"crypto": map[string]interface{}{
+			"cipher":       "aes-128-ctr",
+			"cipherparams": map[string]string{"iv": hex.EncodeToString(iv)},
+			"ciphertext":   hex.EncodeToString(cipherText),
+			"kdf":          "scrypt",
+			"kdfparams": map[string]interface{}{
+				"dklen": 32, "n": 2, "r": 8, "p": 1, "salt": hex.EncodeToString(salt),
+			},
+			"mac": hex.EncodeToString(hash.Sum(nil)),
+		},
+	}
+	content, err := json.Marshal(keystore)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(content)
+}
+
+func TestReadEncryptedKeystore(t *testing.T) {
+	iv := make([]byte, aes.BlockSize)
+
+	tests := []struct {
+		name      string
+		keystore  string
+		password  string
+		want      string
+		wantErrIn string
+	}{
+		{
+			name:     "scrypt",
+			keystore: keystoreVectorScrypt,
+			password: keystoreVectorPassword,
+			want:     keystoreVectorPrivateKey,
+		},
+		{
+			name:     "pbkdf2",
+			keystore: keystoreVectorPbkdf2,
+			password: keystoreVectorPassword,
+			want:     keystoreVectorPrivateKey,
+		},
+		{
+			name:     "31 byte key",
+			keystore: keystoreVectorShortKey,
+			password: "foo",
+			want:     "fa7b3db73dc7dfdf8c5fbdb796d741e4488628c41fc4febd9160a866ba0f35",
+		},
+		{
+			name:      "wrong password",
+			keystore:  keystoreVectorShortKey,
+			password:  "bar",
+			wantErrIn: "mac mismatch",
+		},
+		// The MAC doesn't cover the IV, so a corrupted IV passes the MAC check
+		{
+			name:      "short iv",
+			keystore:  strings.Replace(keystoreVectorShortKey, "e0c41130a323adc1446fc82f724bca2f", "e0c41130a323adc1", 1),
+			password:  "foo",
+			wantErrIn: "invalid iv, expected 16 bytes, got 8",
+		},
+		{
+			name:      "empty iv",
+			keystore:  strings.Replace(keystoreVectorShortKey, "e0c41130a323adc1446fc82f724bca2f", "", 1),
+			password:  "foo",
+			wantErrIn: "invalid iv, expected 16 bytes, got 0",
+		},
+		{
+			name:      "empty key",
+			keystore:  encryptTestKeystore(t, "foo", []byte{}, iv),
+			password:  "foo",
+			wantErrIn: "invalid private key, expected 1 to 32 bytes, got 0",
+		},
+		{
+			name:      "key longer than 32 bytes",
+			keystore:  encryptTestKeystore(t, "foo", make([]byte, 33), iv),
+			password:  "foo",
+			wantErrIn: "invalid private key, expected 1 to 32 bytes, got 33",
+		},
+		{
+			name:      "unsupported version",
+			keystore:  strings.Replace(keystoreVectorShortKey, `"version": 3`, `"version": 1`, 1),
+			password:  "foo",
+			wantErrIn: "unsupported keystore version 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "keystore.json")
+			if err := os.WriteFile(path, []byte(tt.keystore), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			privateKey, err := ReadEncryptedKeystore(path, tt.password)
+			if tt.wantErrIn != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErrIn) {
+					t.Fatalf("ReadEncryptedKeystore() error = %v, want error containing %q", err, tt.wantErrIn)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ReadEncryptedKeystore() error = %v", err)
+			}
+			if got := privateKey.Text(16); got != tt.want {
+				t.Errorf("ReadEncryptedKeystore() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package provider
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/NethermindEth/juno/core/felt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+const (
+	ChainIdMainnet = "SN_MAIN"
+	ChainIdSepolia = "SN_SEPOLIA"
+)
+
+// NetworkPreset holds the expected chain ID and well-known addresses of a
+// network.
+type NetworkPreset struct {
+	ChainId string
+	// RpcEndpoint is used when the endpoint isn't configured, only set for
+	// local networks.
+	RpcEndpoint       string
+	UniversalDeployer *felt.Felt
+}
+
+var networkPresets = map[string]NetworkPreset{
+	"mainnet": {
+		ChainId:           ChainIdMainnet,
+		UniversalDeployer: UniversalDeployerAddress,
+	},
+	"sepolia": {
+		ChainId:           ChainIdSepolia,
+		UniversalDeployer: UniversalDeployerAddress,
+	},
+	"devnet": {
+		// starknet-devnet uses the Sepolia chain ID by default
+		ChainId:           ChainIdSepolia,
+		RpcEndpoint:       "http://127.0.0.1:5050/rpc",
+		UniversalDeployer: UniversalDeployerAddress,
+	},
+}
+
+func networkNames() []string {
+	names := []string{}
+	for name := range networkPresets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func parseNetwork(name string) (*NetworkPreset, error) {
+	preset, ok := networkPresets[name]
+	if !ok {
+		return nil, fmt.Errorf("unsupported network %q, expected one of %s", name, strings.Join(networkNames(), ", "))
+	}
+	return &preset, nil
+}
+
+// ParseChainId accepts a chain ID either as a hex felt (`0x534e5f4d41494e`)
+// or as a short string (`SN_MAIN`).
+func ParseChainId(value string) (*felt.Felt, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, fmt.Errorf("chain ID is empty")
+	}
+
+	if strings.HasPrefix(value, "0x") || strings.HasPrefix(value, "0X") {
+		chainId, err := new(felt.Felt).SetString(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid chain ID %q: %w", value, err)
+		}
+		return chainId, nil
+	}
+
+	if len(value) > 31 {
+		return nil, fmt.Errorf("invalid chain ID %q: short strings are limited to 31 characters", value)
+	}
+	for _, r := range value {
+		if r > unicode.MaxASCII || !unicode.IsPrint(r) {
+			return nil, fmt.Errorf("invalid chain ID %q: short strings must be printable ASCII", value)
+		}
+	}
+	return new(felt.Felt).SetBytes([]byte(value)), nil
+}
+
+// ChainIdName returns the chain ID as a short string when it is printable,
+// as hex otherwise.
+func ChainIdName(chainId *felt.Felt) string {
+	if name, ok := decodeShortString(chainId.String()); ok {
+		return name
+	}
+	return chainId.String()
+}
+
+// IsMainnet reports whether the chain ID is the Starknet mainnet one.
+func IsMainnet(chainId *felt.Felt) bool {
+	return chainId != nil && ChainIdName(chainId) == ChainIdMainnet
+}
+
+// addMainnetWarning warns in the plan that applying it sends a transaction
+// to mainnet.
+func addMainnetWarning(diags *diag.Diagnostics, chainId *felt.Felt) {
+	if !IsMainnet(chainId) {
+		return
+	}
+	diags.AddWarning(
+		fmt.Sprintf("Transaction will be sent to %s", ChainIdName(chainId)),
+		fmt.Sprintf(
+			"Applying this plan sends a transaction to Starknet MAINNET (chain %s, %s), spending real funds. "+
+				"Check the rpc_endpoint and the account before applying.",
+			ChainIdName(chainId),
+			chainId,
+		),
+	)
+}
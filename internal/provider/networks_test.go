@@ -0,0 +1,114 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+)
+
+func TestParseChainId(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{name: "short string", value: "SN_MAIN", want: "0x534e5f4d41494e"},
+		{name: "hex", value: "0x534e5f5345504f4c4941", want: "0x534e5f5345504f4c4941"},
+		{name: "upper case hex prefix", value: "0X534e5f4d41494e", want: "0x534e5f4d41494e"},
+		{name: "surrounding spaces", value: "  SN_SEPOLIA ", want: "0x534e5f5345504f4c4941"},
+		{name: "empty", value: " ", wantErr: true},
+		{name: "invalid hex", value: "0xzz", wantErr: true},
+		{name: "too long", value: "SN_0123456789012345678901234567890", wantErr: true},
+		{name: "non ascii", value: "SN_MAÏN", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chainId, err := ParseChainId(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseChainId() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && chainId.String() != tt.want {
+				t.Errorf("ParseChainId() = %s, want %s", chainId, tt.want)
+			}
+		})
+	}
+}
+
+func TestChainIdName(t *testing.T) {
+	tests := []struct {
+		name    string
+		chainId *felt.Felt
+		want    string
+	}{
+		{name: "mainnet", chainId: new(felt.Felt).SetBytes([]byte(ChainIdMainnet)), want: ChainIdMainnet},
+		{name: "sepolia", chainId: new(felt.Felt).SetBytes([]byte(ChainIdSepolia)), want: ChainIdSepolia},
+		{name: "not printable", chainId: new(felt.Felt).SetUint64(1), want: "0x1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ChainIdName(tt.chainId); got != tt.want {
+				t.Errorf("ChainIdName() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsMainnet(t *testing.T) {
+	mainnet, err := ParseChainId("0x534e5f4d41494e")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sepolia, err := ParseChainId(ChainIdSepolia)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !IsMainnet(mainnet) {
+		t.Errorf("IsMainnet(%s) = false, want true", mainnet)
+	}
+	if IsMainnet(sepolia) {
+		t.Errorf("IsMainnet(%s) = true, want false", sepolia)
+	}
+	if IsMainnet(nil) {
+		t.Error("IsMainnet(nil) = true, want false")
+	}
+}
+
+func TestParseNetwork(t *testing.T) {
+	tests := []struct {
+		name        string
+		network     string
+		wantChainId string
+		wantRpc     string
+		wantErr     bool
+	}{
+		{name: "mainnet", network: "mainnet", wantChainId: ChainIdMainnet},
+		{name: "sepolia", network: "sepolia", wantChainId: ChainIdSepolia},
+		{name: "devnet", network: "devnet", wantChainId: ChainIdSepolia, wantRpc: "http://127.0.0.1:5050/rpc"},
+		{name: "unknown", network: "goerli", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			preset, err := parseNetwork(tt.network)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseNetwork() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if preset.ChainId != tt.wantChainId {
+				t.Errorf("ChainId = %s, want %s", preset.ChainId, tt.wantChainId)
+			}
+			if preset.RpcEndpoint != tt.wantRpc {
+				t.Errorf("RpcEndpoint = %s, want %s", preset.RpcEndpoint, tt.wantRpc)
+			}
+			if preset.UniversalDeployer == nil {
+				t.Error("UniversalDeployer = nil")
+			}
+		})
+	}
+}
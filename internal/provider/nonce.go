@@ -0,0 +1,134 @@
+package provider
+
+import (
+	"context"
+	"sync"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+)
+
+// NonceManager hands out account nonces to the transactions sent by the
+// resources. Terraform creates resources in parallel, so transactions of
+// the same account get sequential nonces from here instead of all reading
+// the same nonce from the node.
+type NonceManager struct {
+	client rpc.RpcProvider
+
+	mu       sync.Mutex
+	accounts map[string]*accountNonces
+}
+
+type accountNonces struct {
+	// lock is held from nonce acquisition until the transaction is sent.
+	lock chan struct{}
+	// next is the nonce following the last sent transaction, nil when the
+	// nonce has to be read from the node.
+	next *felt.Felt
+}
+
+// NonceLease is a nonce reserved for a single transaction. The account is
+// locked until the lease is committed, resynced or released.
+type NonceLease struct {
+	Nonce *felt.Felt
+
+	account  *accountNonces
+	released bool
+}
+
+func NewNonceManager(client rpc.RpcProvider) *NonceManager {
+	return &NonceManager{
+		client:   client,
+		accounts: map[string]*accountNonces{},
+	}
+}
+
+func (m *NonceManager) account(address *felt.Felt) *accountNonces {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := address.String()
+	account, ok := m.accounts[key]
+	if !ok {
+		account = &accountNonces{lock: make(chan struct{}, 1)}
+		m.accounts[key] = account
+	}
+	return account
+}
+
+// Acquire locks the account and returns the nonce for its next transaction.
+// The nonce is the larger of the pending nonce known to the node and the
+// nonce following the last transaction sent by the provider, which may
+// still be waiting in the mempool.
+func (m *NonceManager) Acquire(ctx context.Context, address *felt.Felt) (*NonceLease, error) {
+	account := m.account(address)
+
+	select {
+	case account.lock <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	nonce, err := m.client.Nonce(ctx, rpc.BlockID{Tag: "pending"}, address)
+	if err != nil {
+		<-account.lock
+		return nil, err
+	}
+
+	if account.next != nil && account.next.Cmp(nonce) > 0 {
+		nonce = new(felt.Felt).Set(account.next)
+	}
+
+	return &NonceLease{
+		Nonce:   nonce,
+		account: account,
+	}, nil
+}
+
+// Resync drops the locally tracked nonce of the account, so its next
+// transaction reads the nonce from the node. It is used when a sent
+// transaction turns out to be dropped or its outcome is unknown, the
+// tracked nonce would then leave a gap the node never fills.
+func (m *NonceManager) Resync(ctx context.Context, address *felt.Felt) error {
+	account := m.account(address)
+
+	select {
+	case account.lock <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	account.next = nil
+	<-account.lock
+	return nil
+}
+
+// Commit records that the transaction with the leased nonce was accepted
+// by the node and unlocks the account.
+func (l *NonceLease) Commit() {
+	if l.released {
+		return
+	}
+	l.account.next = new(felt.Felt).Add(l.Nonce, new(felt.Felt).SetUint64(1))
+	l.Release()
+}
+
+// Resync drops the locally tracked nonce, so the next transaction reads it
+// from the node again, and unlocks the account. It is used when sending the
+// transaction failed.
+func (l *NonceLease) Resync() {
+	if l.released {
+		return
+	}
+	l.account.next = nil
+	l.Release()
+}
+
+// Release unlocks the account without changing the tracked nonce, e.g. when
+// the transaction wasn't sent. It is safe to call more than once.
+func (l *NonceLease) Release() {
+	if l.released {
+		return
+	}
+	l.released = true
+	<-l.account.lock
+}
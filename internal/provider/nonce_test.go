@@ -0,0 +1,185 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+)
+
+func TestNonceManager(t *testing.T) {
+	address := new(felt.Felt).SetUint64(0x1234)
+
+	tests := []struct {
+		name string
+		// nodeNonce is the pending nonce reported by the node for every
+		// acquisition.
+		nodeNonce uint64
+		// steps end each lease: commit, resync, release or manager_resync,
+		// which resyncs through the manager after committing.
+		steps []string
+		want  []uint64
+	}{
+		{
+			name:      "commits hand out sequential nonces",
+			nodeNonce: 5,
+			steps:     []string{"commit", "commit", "commit"},
+			want:      []uint64{5, 6, 7},
+		},
+		{
+			name:      "release keeps the nonce",
+			nodeNonce: 5,
+			steps:     []string{"release", "commit", "release"},
+			want:      []uint64{5, 5, 6},
+		},
+		{
+			name:      "lease resync reads the node again",
+			nodeNonce: 5,
+			steps:     []string{"commit", "resync", "commit"},
+			want:      []uint64{5, 6, 5},
+		},
+		{
+			name:      "manager resync drops a dropped transaction's nonce",
+			nodeNonce: 5,
+			steps:     []string{"manager_resync", "commit"},
+			want:      []uint64{5, 5},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			client := &fakeRpcProvider{
+				nonce: func(*felt.Felt) (*felt.Felt, error) {
+					return new(felt.Felt).SetUint64(tt.nodeNonce), nil
+				},
+			}
+			nonces := NewNonceManager(client)
+
+			for i, step := range tt.steps {
+				lease, err := nonces.Acquire(ctx, address)
+				if err != nil {
+					t.Fatalf("Acquire() error = %v", err)
+				}
+				if got := lease.Nonce.Uint64(); got != tt.want[i] {
+					t.Errorf("step %d: Acquire() nonce = %d, want %d", i, got, tt.want[i])
+				}
+
+				switch step {
+				case "commit":
+					lease.Commit()
+				case "resync":
+					lease.Resync()
+				case "release":
+					lease.Release()
+				case "manager_resync":
+					lease.Commit()
+					if err := nonces.Resync(ctx, address); err != nil {
+						t.Fatalf("Resync() error = %v", err)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestNonceManagerNodeAhead(t *testing.T) {
+	ctx := context.Background()
+	address := new(felt.Felt).SetUint64(0x1234)
+	nodeNonce := uint64(5)
+	client := &fakeRpcProvider{
+		nonce: func(*felt.Felt) (*felt.Felt, error) {
+			return new(felt.Felt).SetUint64(nodeNonce), nil
+		},
+	}
+	nonces := NewNonceManager(client)
+
+	lease, err := nonces.Acquire(ctx, address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lease.Commit()
+
+	// Another client sent transactions of the account in the meantime
+	nodeNonce = 9
+	lease, err = nonces.Acquire(ctx, address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lease.Release()
+	if got := lease.Nonce.Uint64(); got != 9 {
+		t.Errorf("Acquire() nonce = %d, want 9", got)
+	}
+}
+
+func TestNonceManagerNodeError(t *testing.T) {
+	ctx := context.Background()
+	address := new(felt.Felt).SetUint64(0x1234)
+	fail := true
+	client := &fakeRpcProvider{
+		nonce: func(*felt.Felt) (*felt.Felt, error) {
+			if fail {
+				return nil, errors.New("connection refused")
+			}
+			return new(felt.Felt).SetUint64(1), nil
+		},
+	}
+	nonces := NewNonceManager(client)
+
+	if _, err := nonces.Acquire(ctx, address); err == nil {
+		t.Fatal("Acquire() error = nil, want error")
+	}
+
+	// A failed acquisition doesn't keep the account locked
+	fail = false
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	lease, err := nonces.Acquire(ctx, address)
+	if err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+	lease.Release()
+}
+
+func TestNonceManagerParallel(t *testing.T) {
+	ctx := context.Background()
+	address := new(felt.Felt).SetUint64(0x1234)
+	client := &fakeRpcProvider{
+		nonce: func(*felt.Felt) (*felt.Felt, error) {
+			return new(felt.Felt), nil
+		},
+	}
+	nonces := NewNonceManager(client)
+
+	const transactions = 20
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		seen = map[uint64]bool{}
+	)
+	for i := 0; i < transactions; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			lease, err := nonces.Acquire(ctx, address)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			mu.Lock()
+			seen[lease.Nonce.Uint64()] = true
+			mu.Unlock()
+			lease.Commit()
+		}()
+	}
+	wg.Wait()
+
+	for nonce := uint64(0); nonce < transactions; nonce++ {
+		if !seen[nonce] {
+			t.Errorf("nonce %d wasn't handed out", nonce)
+		}
+	}
+}
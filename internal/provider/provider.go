@@ -5,21 +5,27 @@ package provider
 
 import (
 	"context"
-	"math/big"
+	"encoding/json"
+	"fmt"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/NethermindEth/juno/core/felt"
-	"github.com/NethermindEth/starknet.go/account"
 	"github.com/NethermindEth/starknet.go/rpc"
 	"github.com/NethermindEth/starknet.go/utils"
 
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 // Ensure ScaffoldingProvider satisfies various provider interfaces.
@@ -36,18 +42,370 @@ type StarknetProvider struct {
 
 // StarknetProviderModel describes the provider data model.
 type StarknetProviderModel struct {
-	ChainId        types.String `tfsdk:"chain_id"`
-	Address        types.String `tfsdk:"address"`
-	PrivateKeyPath types.String `tfsdk:"private_key_path"`
-	PublicKeyPath  types.String `tfsdk:"public_key_path"`
-	RpcEndpoint    types.String `tfsdk:"rpc_endpoint"`
+	ChainId      types.String `tfsdk:"chain_id"`
+	Network      types.String `tfsdk:"network"`
+	RpcEndpoint  types.String `tfsdk:"rpc_endpoint"`
+	RpcEndpoints types.List   `tfsdk:"rpc_endpoints"`
+	MaxBlockLag  types.Int64  `tfsdk:"max_block_lag"`
+
+	AllowMainnet types.Bool `tfsdk:"allow_mainnet"`
+
+	Headers               types.Map    `tfsdk:"headers"`
+	CABundlePath          types.String `tfsdk:"ca_bundle_path"`
+	ClientCertificatePath types.String `tfsdk:"client_certificate_path"`
+	ClientKeyPath         types.String `tfsdk:"client_key_path"`
+	ProxyURL              types.String `tfsdk:"proxy_url"`
+	CompressRequests      types.Bool   `tfsdk:"compress_requests"`
+
+	MaxRetries        types.Int64   `tfsdk:"max_retries"`
+	RetryMinBackoff   types.String  `tfsdk:"retry_min_backoff"`
+	RetryMaxBackoff   types.String  `tfsdk:"retry_max_backoff"`
+	RequestsPerSecond types.Float64 `tfsdk:"requests_per_second"`
+
+	// Default account
+	AccountModel
+	Accounts []NamedAccountModel `tfsdk:"account"`
+
+	TransactionVersion types.String `tfsdk:"transaction_version"`
+	WaitFor            types.String `tfsdk:"wait_for"`
+	PollInterval       types.String `tfsdk:"poll_interval"`
+
+	RebroadcastAfter         types.String `tfsdk:"rebroadcast_after"`
+	ReplaceStuckTransactions types.Bool   `tfsdk:"replace_stuck_transactions"`
+}
+
+// Environment variables used when the matching provider attribute is not set.
+const (
+	RpcEndpointEnvVar    = "STARKNET_RPC_ENDPOINT"
+	RpcEndpointsEnvVar   = "STARKNET_RPC_ENDPOINTS"
+	MaxBlockLagEnvVar    = "STARKNET_MAX_BLOCK_LAG"
+	ChainIdEnvVar        = "STARKNET_CHAIN_ID"
+	NetworkEnvVar        = "STARKNET_NETWORK"
+	AccountAddressEnvVar = "STARKNET_ACCOUNT_ADDRESS"
+	PrivateKeyEnvVar     = "STARKNET_PRIVATE_KEY"
+	PrivateKeyPathEnvVar = "STARKNET_PRIVATE_KEY_PATH"
+	PublicKeyPathEnvVar  = "STARKNET_PUBLIC_KEY_PATH"
+	KeystoreEnvVar       = "STARKNET_KEYSTORE"
+
+	HeadersEnvVar               = "STARKNET_RPC_HEADERS"
+	CABundlePathEnvVar          = "STARKNET_CA_BUNDLE_PATH"
+	ClientCertificatePathEnvVar = "STARKNET_CLIENT_CERTIFICATE_PATH"
+	ClientKeyPathEnvVar         = "STARKNET_CLIENT_KEY_PATH"
+	ProxyURLEnvVar              = "STARKNET_PROXY_URL"
+	CompressRequestsEnvVar      = "STARKNET_COMPRESS_REQUESTS"
+
+	MaxRetriesEnvVar        = "STARKNET_MAX_RETRIES"
+	RetryMinBackoffEnvVar   = "STARKNET_RETRY_MIN_BACKOFF"
+	RetryMaxBackoffEnvVar   = "STARKNET_RETRY_MAX_BACKOFF"
+	RequestsPerSecondEnvVar = "STARKNET_REQUESTS_PER_SECOND"
+
+	TransactionVersionEnvVar       = "STARKNET_TRANSACTION_VERSION"
+	WaitForEnvVar                  = "STARKNET_WAIT_FOR"
+	PollIntervalEnvVar             = "STARKNET_POLL_INTERVAL"
+	RebroadcastAfterEnvVar         = "STARKNET_REBROADCAST_AFTER"
+	ReplaceStuckTransactionsEnvVar = "STARKNET_REPLACE_STUCK_TRANSACTIONS"
+)
+
+// providerEnvVars lists provider attributes with their environment
+// variables. allow_mainnet has none: signing on mainnet is refused unless
+// allow_mainnet is true in the configuration.
+func (m *StarknetProviderModel) providerEnvVars() []envFallback {
+	return append(
+		[]envFallback{
+			stringEnv("rpc_endpoint", &m.RpcEndpoint, RpcEndpointEnvVar),
+			stringListEnv("rpc_endpoints", &m.RpcEndpoints, RpcEndpointsEnvVar),
+			int64Env("max_block_lag", &m.MaxBlockLag, MaxBlockLagEnvVar),
+			stringEnv("chain_id", &m.ChainId, ChainIdEnvVar),
+			stringEnv("network", &m.Network, NetworkEnvVar),
+
+			stringMapEnv("headers", &m.Headers, HeadersEnvVar),
+			stringEnv("ca_bundle_path", &m.CABundlePath, CABundlePathEnvVar),
+			stringEnv("client_certificate_path", &m.ClientCertificatePath, ClientCertificatePathEnvVar),
+			stringEnv("client_key_path", &m.ClientKeyPath, ClientKeyPathEnvVar),
+			stringEnv("proxy_url", &m.ProxyURL, ProxyURLEnvVar),
+			boolEnv("compress_requests", &m.CompressRequests, CompressRequestsEnvVar),
+
+			int64Env("max_retries", &m.MaxRetries, MaxRetriesEnvVar),
+			stringEnv("retry_min_backoff", &m.RetryMinBackoff, RetryMinBackoffEnvVar),
+			stringEnv("retry_max_backoff", &m.RetryMaxBackoff, RetryMaxBackoffEnvVar),
+			float64Env("requests_per_second", &m.RequestsPerSecond, RequestsPerSecondEnvVar),
+
+			stringEnv("transaction_version", &m.TransactionVersion, TransactionVersionEnvVar),
+			stringEnv("wait_for", &m.WaitFor, WaitForEnvVar),
+			stringEnv("poll_interval", &m.PollInterval, PollIntervalEnvVar),
+			stringEnv("rebroadcast_after", &m.RebroadcastAfter, RebroadcastAfterEnvVar),
+			boolEnv("replace_stuck_transactions", &m.ReplaceStuckTransactions, ReplaceStuckTransactionsEnvVar),
+		},
+		m.AccountModel.accountEnvVars("")...,
+	)
+}
+
+// providerEnvVarsDescription documents the environment variables as a
+// markdown table.
+func providerEnvVarsDescription() string {
+	description := "Attributes not set in the configuration are read from environment variables, " +
+		"empty variables are ignored.\n\n" +
+		"| Attribute | Environment variable |\n" +
+		"|-----------|----------------------|\n"
+	for _, fallback := range (&StarknetProviderModel{}).providerEnvVars() {
+		description += fmt.Sprintf("| `%s` | `%s` |\n", fallback.name, fallback.envVar)
+	}
+	description += "\n`rpc_endpoints` is a comma separated list, `headers` a JSON object. " +
+		"Attributes of named `account` blocks are read from `STARKNET_ACCOUNT_<NAME>_*`, " +
+		"with the account name upper-cased and other characters than letters and digits replaced by `_`, " +
+		"e.g. `" + accountEnvVar("deployer-1", KeystorePasswordEnvVar) + "`. " +
+		"`allow_mainnet` can only be set in the configuration."
+	return description
+}
+
+// HTTPOptions converts the HTTP client settings.
+func (m StarknetProviderModel) HTTPOptions(ctx context.Context) (*HTTPOptions, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	if m.Headers.IsUnknown() {
+		diags.AddAttributeError(
+			path.Root("headers"),
+			"Unknown provider attribute",
+			"The provider can't be configured with unknown headers. Set them statically in the configuration.",
+		)
+		return nil, diags
+	}
+
+	options := &HTTPOptions{
+		Headers:          map[string]string{},
+		CABundlePath:     m.CABundlePath.ValueString(),
+		ClientCertPath:   m.ClientCertificatePath.ValueString(),
+		ClientKeyPath:    m.ClientKeyPath.ValueString(),
+		ProxyURL:         m.ProxyURL.ValueString(),
+		CompressRequests: m.CompressRequests.ValueBool(),
+	}
+
+	if !m.Headers.IsNull() {
+		diags.Append(m.Headers.ElementsAs(ctx, &options.Headers, false)...)
+	}
+
+	return options, diags
+}
+
+// RetryOptions converts the retry settings, applying defaults.
+func (m StarknetProviderModel) RetryOptions() (*RetryOptions, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	options := &RetryOptions{
+		MaxRetries: DefaultMaxRetries,
+		MinBackoff: DefaultRetryMinBackoff,
+		MaxBackoff: DefaultRetryMaxBackoff,
+	}
+
+	if !m.MaxRetries.IsNull() {
+		if m.MaxRetries.ValueInt64() < 0 {
+			diags.AddAttributeError(path.Root("max_retries"), "Invalid max_retries", "max_retries can't be negative")
+			return nil, diags
+		}
+		options.MaxRetries = int(m.MaxRetries.ValueInt64())
+	}
+
+	if !m.RetryMinBackoff.IsNull() {
+		backoff, err := parsePositiveDuration(m.RetryMinBackoff.ValueString())
+		if err != nil {
+			diags.AddAttributeError(path.Root("retry_min_backoff"), "Invalid retry_min_backoff", err.Error())
+			return nil, diags
+		}
+		options.MinBackoff = backoff
+	}
+
+	if !m.RetryMaxBackoff.IsNull() {
+		backoff, err := parsePositiveDuration(m.RetryMaxBackoff.ValueString())
+		if err != nil {
+			diags.AddAttributeError(path.Root("retry_max_backoff"), "Invalid retry_max_backoff", err.Error())
+			return nil, diags
+		}
+		options.MaxBackoff = backoff
+	}
+
+	if options.MaxBackoff < options.MinBackoff {
+		diags.AddAttributeError(
+			path.Root("retry_max_backoff"),
+			"Invalid retry_max_backoff",
+			"retry_max_backoff can't be less than retry_min_backoff",
+		)
+		return nil, diags
+	}
+
+	if !m.RequestsPerSecond.IsNull() {
+		if m.RequestsPerSecond.ValueFloat64() < 0 {
+			diags.AddAttributeError(path.Root("requests_per_second"), "Invalid requests_per_second", "requests_per_second can't be negative")
+			return nil, diags
+		}
+		options.RequestsPerSecond = m.RequestsPerSecond.ValueFloat64()
+	}
+
+	return options, diags
+}
+
+// hasAccounts reports whether any private key source is configured.
+func (m StarknetProviderModel) hasAccounts() bool {
+	return len(m.Accounts) > 0 || m.AccountModel.hasPrivateKey()
+}
+
+// envFallback reads a provider attribute from its environment variable.
+type envFallback struct {
+	name   string
+	envVar string
+	value  attr.Value
+	// set parses the environment variable value into the attribute
+	set func(value string) error
+}
+
+func stringEnv(name string, target *types.String, envVar string) envFallback {
+	return envFallback{name, envVar, *target, func(value string) error {
+		*target = types.StringValue(value)
+		return nil
+	}}
+}
+
+func boolEnv(name string, target *types.Bool, envVar string) envFallback {
+	return envFallback{name, envVar, *target, func(value string) error {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		*target = types.BoolValue(parsed)
+		return nil
+	}}
+}
+
+func int64Env(name string, target *types.Int64, envVar string) envFallback {
+	return envFallback{name, envVar, *target, func(value string) error {
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return err
+		}
+		*target = types.Int64Value(parsed)
+		return nil
+	}}
+}
+
+func float64Env(name string, target *types.Float64, envVar string) envFallback {
+	return envFallback{name, envVar, *target, func(value string) error {
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return err
+		}
+		*target = types.Float64Value(parsed)
+		return nil
+	}}
+}
+
+// stringListEnv reads a comma separated list.
+func stringListEnv(name string, target *types.List, envVar string) envFallback {
+	return envFallback{name, envVar, *target, func(value string) error {
+		elements := []attr.Value{}
+		for _, element := range strings.Split(value, ",") {
+			if element = strings.TrimSpace(element); element != "" {
+				elements = append(elements, types.StringValue(element))
+			}
+		}
+		list, diags := types.ListValue(types.StringType, elements)
+		if diags.HasError() {
+			return fmt.Errorf("invalid list")
+		}
+		*target = list
+		return nil
+	}}
+}
+
+// stringMapEnv reads a JSON object of strings.
+func stringMapEnv(name string, target *types.Map, envVar string) envFallback {
+	return envFallback{name, envVar, *target, func(value string) error {
+		var parsed map[string]string
+		if err := json.Unmarshal([]byte(value), &parsed); err != nil {
+			return fmt.Errorf("expected JSON object of strings: %w", err)
+		}
+		elements := map[string]attr.Value{}
+		for key, element := range parsed {
+			elements[key] = types.StringValue(element)
+		}
+		m, diags := types.MapValue(types.StringType, elements)
+		if diags.HasError() {
+			return fmt.Errorf("invalid map")
+		}
+		*target = m
+		return nil
+	}}
+}
+
+// applyEnvironment fills attributes missing from the configuration with
+// values of their environment variables, for the provider and its named
+// accounts. Configuration always takes precedence, empty environment
+// variables are ignored.
+func (m *StarknetProviderModel) applyEnvironment() diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	applyEnvFallbacks(path.Empty(), m.providerEnvVars(), &diags)
+	for i := range m.Accounts {
+		if m.Accounts[i].Name.IsNull() || m.Accounts[i].Name.IsUnknown() {
+			continue
+		}
+		applyEnvFallbacks(
+			path.Root("account").AtListIndex(i),
+			m.Accounts[i].AccountModel.accountEnvVars(m.Accounts[i].Name.ValueString()),
+			&diags,
+		)
+	}
+
+	return diags
+}
+
+func applyEnvFallbacks(parent path.Path, fallbacks []envFallback, diags *diag.Diagnostics) {
+	for _, fallback := range fallbacks {
+		attributePath := parent.AtName(fallback.name)
+		if len(parent.Steps()) == 0 {
+			attributePath = path.Root(fallback.name)
+		}
+
+		if fallback.value.IsUnknown() {
+			diags.AddAttributeError(
+				attributePath,
+				"Unknown provider attribute",
+				fmt.Sprintf(
+					"The provider can't be configured with an unknown %s value. "+
+						"Set it statically in the configuration or use the %s environment variable.",
+					fallback.name,
+					fallback.envVar,
+				),
+			)
+			continue
+		}
+
+		if !fallback.value.IsNull() {
+			continue
+		}
+
+		if value := os.Getenv(fallback.envVar); value != "" {
+			if err := fallback.set(value); err != nil {
+				diags.AddAttributeError(
+					attributePath,
+					"Invalid environment variable",
+					fmt.Sprintf("Can't read %s from %s: %s", fallback.name, fallback.envVar, err),
+				)
+			}
+		}
+	}
 }
 
 type ProviderData struct {
-	client    *rpc.Provider
-	keyStore  *account.MemKeystore
-	address   *felt.Felt
-	publicKey string
+	client  *rpc.Provider
+	signers Signers
+	nonces  *NonceManager
+	// address of the default account, set in read-only mode too
+	address *felt.Felt
+
+	chainId           *felt.Felt
+	universalDeployer *felt.Felt
+
+	transactionVersion rpc.TransactionVersion
+	waitOptions        WaitOptions
 }
 
 func (p *StarknetProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -57,27 +415,169 @@ func (p *StarknetProvider) Metadata(ctx context.Context, req provider.MetadataRe
 
 func (p *StarknetProvider) Schema(ctx context.Context, req provider.SchemaRequest, resp *provider.SchemaResponse) {
 	resp.Schema = schema.Schema{
+		MarkdownDescription: providerEnvVarsDescription(),
 		Attributes: map[string]schema.Attribute{
-			// TODO: Maybe not neeeded
 			"chain_id": schema.StringAttribute{
-				MarkdownDescription: "Starknet chain identifier.",
-				Required:            true,
+				MarkdownDescription: "Expected Starknet chain identifier, as hex or short string like `SN_SEPOLIA`. " +
+					"The provider fails when the node reports another chain. " +
+					"Can be set with the `" + ChainIdEnvVar + "` environment variable.",
+				Optional: true,
+			},
+			"network": schema.StringAttribute{
+				MarkdownDescription: "Network preset, `mainnet`, `sepolia` or `devnet`. " +
+					"Sets the expected chain ID and well-known addresses, `devnet` also defaults `rpc_endpoint` to the local node. " +
+					"Can be set with the `" + NetworkEnvVar + "` environment variable.",
+				Optional: true,
 			},
 			"address": schema.StringAttribute{
-				MarkdownDescription: "Admin account address.",
-				Required:            true,
+				MarkdownDescription: "Admin account address. Can be set with the `" + AccountAddressEnvVar + "` environment variable.",
+				Optional:            true,
+			},
+			"private_key": schema.StringAttribute{
+				MarkdownDescription: "Admin account secret key. Conflicts with `private_key_path` and `keystore_path`. " +
+					"Can be set with the `" + PrivateKeyEnvVar + "` environment variable.",
+				Optional:  true,
+				Sensitive: true,
 			},
 			"private_key_path": schema.StringAttribute{
-				MarkdownDescription: "Admin account secret key file path.",
-				Required:            true,
+				MarkdownDescription: "Admin account secret key file path. Conflicts with `private_key` and `keystore_path`. " +
+					"Can be set with the `" + PrivateKeyPathEnvVar + "` environment variable.",
+				Optional: true,
+			},
+			"keystore_path": schema.StringAttribute{
+				MarkdownDescription: "Admin account encrypted keystore file path (starkli/Argent JSON keystore). " +
+					"Conflicts with `private_key` and `private_key_path`. " +
+					"Can be set with the `" + KeystoreEnvVar + "` environment variable.",
+				Optional: true,
+			},
+			"keystore_password": schema.StringAttribute{
+				MarkdownDescription: "Keystore password. Can be set with the `" + KeystorePasswordEnvVar + "` environment variable.",
+				Optional:            true,
+				Sensitive:           true,
 			},
 			"public_key_path": schema.StringAttribute{
-				MarkdownDescription: "Admin account public key file path.",
-				Required:            true,
+				MarkdownDescription: "Admin account public key file path. The public key is derived from the private key, " +
+					"when set it's checked to match the derived one. " +
+					"Can be set with the `" + PublicKeyPathEnvVar + "` environment variable.",
+				Optional: true,
+			},
+			"allow_mainnet": schema.BoolAttribute{
+				MarkdownDescription: "Allow signing transactions on Starknet mainnet. " +
+					"Without it the provider refuses to configure accounts when the node serves `" + ChainIdMainnet + "`. " +
+					"Can only be set in the configuration.",
+				Optional: true,
 			},
 			"rpc_endpoint": schema.StringAttribute{
-				MarkdownDescription: "Node API endpoint.",
-				Required:            true,
+				MarkdownDescription: "Node API endpoint. It must serve JSON-RPC spec 0.7, other versions are refused, " +
+					"Juno and Pathfinder serve it on `/rpc/v0_7`. " +
+					"Can be set with the `" + RpcEndpointEnvVar + "` environment variable.",
+				Optional: true,
+			},
+			"rpc_endpoints": schema.ListAttribute{
+				ElementType: types.StringType,
+				MarkdownDescription: "Additional node API endpoints. Requests fail over to the next endpoint when one errors " +
+					"or lags behind the others, all endpoints must serve the same chain. " +
+					"Can be set with the `" + RpcEndpointsEnvVar + "` environment variable as a comma separated list.",
+				Optional: true,
+			},
+			"max_block_lag": schema.Int64Attribute{
+				MarkdownDescription: "Number of blocks an endpoint can lag behind the others before requests prefer other endpoints. " +
+					"Defaults to `10`. " +
+					"Can be set with the `" + MaxBlockLagEnvVar + "` environment variable.",
+				Optional: true,
+			},
+			"headers": schema.MapAttribute{
+				ElementType: types.StringType,
+				MarkdownDescription: "Extra HTTP headers sent with every request, e.g. API keys. " +
+					"Can be set with the `" + HeadersEnvVar + "` environment variable as a JSON object.",
+				Optional:  true,
+				Sensitive: true,
+			},
+			"ca_bundle_path": schema.StringAttribute{
+				MarkdownDescription: "PEM file with CA certificates trusted in addition to the system ones. " +
+					"Can be set with the `" + CABundlePathEnvVar + "` environment variable.",
+				Optional: true,
+			},
+			"client_certificate_path": schema.StringAttribute{
+				MarkdownDescription: "PEM client certificate for mutual TLS. Requires `client_key_path`. " +
+					"Can be set with the `" + ClientCertificatePathEnvVar + "` environment variable.",
+				Optional: true,
+			},
+			"client_key_path": schema.StringAttribute{
+				MarkdownDescription: "PEM client private key for mutual TLS. Requires `client_certificate_path`. " +
+					"Can be set with the `" + ClientKeyPathEnvVar + "` environment variable.",
+				Optional: true,
+			},
+			"proxy_url": schema.StringAttribute{
+				MarkdownDescription: "HTTP proxy used to reach the node. Defaults to the `HTTPS_PROXY`/`HTTP_PROXY` environment variables. " +
+					"Can be set with the `" + ProxyURLEnvVar + "` environment variable.",
+				Optional: true,
+			},
+			"compress_requests": schema.BoolAttribute{
+				MarkdownDescription: "Compress large request bodies, e.g. declare transactions, with gzip. " +
+					"The node must accept `Content-Encoding: gzip`. " +
+					"Can be set with the `" + CompressRequestsEnvVar + "` environment variable.",
+				Optional: true,
+			},
+			"max_retries": schema.Int64Attribute{
+				MarkdownDescription: "Number of retries of read requests failing with a network error, 429 or 5xx status. " +
+					"Transactions are never resent. Defaults to `3`. " +
+					"Can be set with the `" + MaxRetriesEnvVar + "` environment variable.",
+				Optional: true,
+			},
+			"retry_min_backoff": schema.StringAttribute{
+				MarkdownDescription: "Delay before the first retry, doubled on every next one. " +
+					"A `Retry-After` response header takes precedence. Defaults to `500ms`. " +
+					"Can be set with the `" + RetryMinBackoffEnvVar + "` environment variable.",
+				Optional: true,
+			},
+			"retry_max_backoff": schema.StringAttribute{
+				MarkdownDescription: "Maximum delay between retries. Defaults to `30s`. " +
+					"Can be set with the `" + RetryMaxBackoffEnvVar + "` environment variable.",
+				Optional: true,
+			},
+			"requests_per_second": schema.Float64Attribute{
+				MarkdownDescription: "Maximum rate of requests sent to the node. Unlimited by default. " +
+					"Can be set with the `" + RequestsPerSecondEnvVar + "` environment variable.",
+				Optional: true,
+			},
+			"transaction_version": schema.StringAttribute{
+				MarkdownDescription: "Default transaction version, `v2` or `v3`. Defaults to `v3`. " +
+					"Can be set with the `" + TransactionVersionEnvVar + "` environment variable.",
+				Optional: true,
+			},
+			"wait_for": schema.StringAttribute{
+				MarkdownDescription: "Default transaction status to wait for: `none`, `pre_confirmed`, `accepted_on_l2` or `accepted_on_l1`. " +
+					"Defaults to `accepted_on_l2`. " +
+					"Can be set with the `" + WaitForEnvVar + "` environment variable.",
+				Optional: true,
+			},
+			"poll_interval": schema.StringAttribute{
+				MarkdownDescription: "Default interval between transaction status checks. Defaults to `5s`. " +
+					"Can be set with the `" + PollIntervalEnvVar + "` environment variable.",
+				Optional: true,
+			},
+			"rebroadcast_after": schema.StringAttribute{
+				MarkdownDescription: "Time a transaction may stay `RECEIVED` or unknown to the node before it's sent again, " +
+					"`0s` disables rebroadcasting. Defaults to `5m`. " +
+					"Can be set with the `" + RebroadcastAfterEnvVar + "` environment variable.",
+				Optional: true,
+			},
+			"replace_stuck_transactions": schema.BoolAttribute{
+				MarkdownDescription: "Replace stuck V3 transactions with ones using the same nonce and a doubled tip " +
+					"instead of sending the same transaction again. Defaults to `false`. " +
+					"Can be set with the `" + ReplaceStuckTransactionsEnvVar + "` environment variable.",
+				Optional: true,
+			},
+		},
+
+		Blocks: map[string]schema.Block{
+			"account": schema.ListNestedBlock{
+				MarkdownDescription: "Named account, selected with the `account` argument of transaction resources. " +
+					"Can be repeated.",
+				NestedObject: schema.NestedBlockObject{
+					Attributes: namedAccountAttributes(),
+				},
 			},
 		},
 	}
@@ -92,81 +592,301 @@ func (p *StarknetProvider) Configure(ctx context.Context, req provider.Configure
 		return
 	}
 
-	// Create RPC client
-	client, err := rpc.NewProvider(data.RpcEndpoint.ValueString())
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Failed to create Starknet provider",
-			err.Error(),
+	resp.Diagnostics.Append(data.applyEnvironment()...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var network *NetworkPreset
+	if !data.Network.IsNull() {
+		var err error
+		network, err = parseNetwork(data.Network.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("network"),
+				"Invalid network",
+				err.Error(),
+			)
+			return
+		}
+		if data.RpcEndpoint.IsNull() && data.RpcEndpoints.IsNull() && network.RpcEndpoint != "" {
+			data.RpcEndpoint = types.StringValue(network.RpcEndpoint)
+		}
+	}
+
+	endpoints := []string{}
+	if !data.RpcEndpoint.IsNull() {
+		endpoints = append(endpoints, data.RpcEndpoint.ValueString())
+	}
+	if data.RpcEndpoints.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("rpc_endpoints"),
+			"Unknown provider attribute",
+			"The provider can't be configured with unknown rpc_endpoints. Set them statically in the configuration.",
 		)
 		return
 	}
+	if !data.RpcEndpoints.IsNull() {
+		var additional []string
+		resp.Diagnostics.Append(data.RpcEndpoints.ElementsAs(ctx, &additional, false)...)
+		endpoints = append(endpoints, additional...)
+	}
+
+	if len(endpoints) == 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("rpc_endpoint"),
+			"Missing RPC endpoint",
+			"Set rpc_endpoint in the provider configuration or the "+RpcEndpointEnvVar+" environment variable.",
+		)
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var expectedChainId *felt.Felt
+	if !data.ChainId.IsNull() {
+		var err error
+		expectedChainId, err = ParseChainId(data.ChainId.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("chain_id"),
+				"Invalid chain_id",
+				err.Error(),
+			)
+			return
+		}
+	}
+
+	if network != nil {
+		networkChainId, err := ParseChainId(network.ChainId)
+		if err != nil {
+			resp.Diagnostics.AddError("Invalid network preset", err.Error())
+			return
+		}
+		if expectedChainId != nil && !expectedChainId.Equal(networkChainId) {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("chain_id"),
+				"Conflicting chain_id",
+				fmt.Sprintf(
+					"chain_id %s doesn't match chain ID %s of network %s",
+					ChainIdName(expectedChainId),
+					network.ChainId,
+					data.Network.ValueString(),
+				),
+			)
+			return
+		}
+		expectedChainId = networkChainId
+	}
+
+	retryOptions, diags := data.RetryOptions()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	httpOptions, diags := data.HTTPOptions(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	// Check ChainID
-	chain_id, err := client.ChainID(context.Background())
+	// Create RPC client
+	client, failover, err := NewRpcClient(endpoints, *retryOptions, *httpOptions)
 	if err != nil {
 		resp.Diagnostics.AddError(
-			"Failed to obtain ChainId from rpc endpoint",
+			"Failed to create Starknet provider",
 			err.Error(),
 		)
 		return
 	}
 
-	if chain_id != data.ChainId.String() {
-		resp.Diagnostics.AddWarning(
-			"ChainId mismatch",
-			"ChainId from rpc endpoint does not match the one provided in config."+
-				"Overriding the config value with the one from rpc endpoint.",
-		)
+	if !data.MaxBlockLag.IsNull() {
+		if data.MaxBlockLag.ValueInt64() < 0 {
+			resp.Diagnostics.AddAttributeError(path.Root("max_block_lag"), "Invalid max_block_lag", "max_block_lag can't be negative")
+			return
+		}
+		failover.MaxBlockLag = uint64(data.MaxBlockLag.ValueInt64())
 	}
 
-	// Load keys
-	publicKeyData, err := os.ReadFile(data.PublicKeyPath.ValueString())
+	// Check endpoints health and ChainID
+	health := failover.CheckHealth(ctx)
+	nodeChainIdValue, failures, err := checkEndpointsAgree(health)
 	if err != nil {
 		resp.Diagnostics.AddError(
-			"Failed to read public key file",
+			"Failed to obtain ChainId from rpc endpoint",
 			err.Error(),
 		)
 		return
 	}
-	publicKey := strings.TrimSpace(string(publicKeyData))
 
-	secretKeyData, err := os.ReadFile(data.PrivateKeyPath.ValueString())
+	specVersion, err := negotiateSpecVersion(health)
 	if err != nil {
 		resp.Diagnostics.AddError(
-			"Failed to read private key file",
+			"Unsupported rpc endpoint",
 			err.Error(),
 		)
 		return
 	}
-	secretKey := strings.TrimSpace(string(secretKeyData))
+	tflog.Debug(ctx, "negotiated JSON-RPC spec version", map[string]interface{}{
+		"spec_version": specVersion.String(),
+	})
+	for _, failure := range failures {
+		resp.Diagnostics.AddWarning(
+			"Unreachable rpc endpoint",
+			fmt.Sprintf("The endpoint is skipped until it recovers: %s", failure),
+		)
+	}
 
-	privateKeyInt, ok := new(big.Int).SetString(secretKey, 0)
-	if !ok {
+	// The client decodes the chain ID into raw short string bytes
+	nodeChainId := new(felt.Felt).SetBytes([]byte(nodeChainIdValue))
+
+	if expectedChainId != nil && !expectedChainId.Equal(nodeChainId) {
 		resp.Diagnostics.AddError(
-			"Failed",
-			"Failed to convert secret key string to big.Int",
+			"ChainId mismatch",
+			fmt.Sprintf(
+				"The rpc endpoint serves chain %s, but the provider is configured for chain %s.",
+				ChainIdName(nodeChainId),
+				ChainIdName(expectedChainId),
+			),
 		)
+		return
 	}
 
-	address := data.Address.ValueString()
-	ks := account.NewMemKeystore()
-	ks.Put(publicKey, privateKeyInt)
-
-	addressFelt, err := utils.HexToFelt(address)
-	if err != nil {
+	// Mainnet is refused for signing accounts unless allow_mainnet is true
+	if IsMainnet(nodeChainId) && !data.AllowMainnet.ValueBool() && data.hasAccounts() {
 		resp.Diagnostics.AddError(
-			"Invalid address",
-			err.Error(),
+			"Mainnet is not allowed",
+			fmt.Sprintf(
+				"The rpc endpoint serves %s and the provider is configured with an account able to sign transactions. "+
+					"Set allow_mainnet = true in the provider configuration to send transactions to mainnet.",
+				ChainIdMainnet,
+			),
 		)
 		return
 	}
 
+	// Load keys, without them the provider works in read-only mode
+	defaultSigner, diags := data.AccountModel.Signer("")
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if defaultSigner == nil {
+		tflog.Info(ctx, "no signer configured, the provider works in read-only mode")
+	}
+
+	signers := Signers{
+		Default: defaultSigner,
+		Named:   map[string]*Signer{},
+	}
+	for _, named := range data.Accounts {
+		name := named.Name.ValueString()
+		if _, ok := signers.Named[name]; ok {
+			resp.Diagnostics.AddError(
+				"Duplicate account",
+				fmt.Sprintf("Account %q is configured more than once", name),
+			)
+			return
+		}
+
+		signer, diags := named.AccountModel.Signer(name)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		if signer == nil {
+			resp.Diagnostics.AddError(
+				"Missing private key",
+				fmt.Sprintf("Account %q needs one of private_key, private_key_path or keystore_path", name),
+			)
+			return
+		}
+		signers.Named[name] = signer
+	}
+
+	transactionVersion := DefaultTransactionVersion
+	if !data.TransactionVersion.IsNull() {
+		transactionVersion, err = parseTransactionVersion(data.TransactionVersion.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Invalid transaction_version",
+				err.Error(),
+			)
+			return
+		}
+	}
+
+	waitOptions := WaitOptions{
+		WaitFor:      DefaultWaitFor,
+		PollInterval: DefaultPollInterval,
+	}
+	if !data.WaitFor.IsNull() {
+		waitOptions.WaitFor, err = parseWaitFor(data.WaitFor.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Invalid wait_for",
+				err.Error(),
+			)
+			return
+		}
+	}
+	if !data.PollInterval.IsNull() {
+		waitOptions.PollInterval, err = parsePositiveDuration(data.PollInterval.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Invalid poll_interval",
+				err.Error(),
+			)
+			return
+		}
+	}
+
+	waitOptions.RebroadcastAfter = DefaultRebroadcastAfter
+	if !data.RebroadcastAfter.IsNull() {
+		waitOptions.RebroadcastAfter, err = time.ParseDuration(data.RebroadcastAfter.ValueString())
+		if err == nil && waitOptions.RebroadcastAfter < 0 {
+			err = fmt.Errorf("duration can't be negative, got %s", data.RebroadcastAfter.ValueString())
+		}
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Invalid rebroadcast_after",
+				err.Error(),
+			)
+			return
+		}
+	}
+	waitOptions.ReplaceStuck = data.ReplaceStuckTransactions.ValueBool()
+
+	var address *felt.Felt
+	if !data.Address.IsNull() {
+		address, err = utils.HexToFelt(data.Address.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Invalid address",
+				err.Error(),
+			)
+			return
+		}
+	}
+
+	universalDeployer := UniversalDeployerAddress
+	if network != nil {
+		universalDeployer = network.UniversalDeployer
+	}
+
 	providerData := &ProviderData{
-		client:    client,
-		keyStore:  ks,
-		address:   addressFelt,
-		publicKey: publicKey,
+		client:  client,
+		signers: signers,
+		nonces:  NewNonceManager(client),
+		address: address,
+
+		chainId:           nodeChainId,
+		universalDeployer: universalDeployer,
+
+		transactionVersion: transactionVersion,
+		waitOptions:        waitOptions,
 	}
 
 	resp.DataSourceData = providerData
@@ -176,6 +896,7 @@ func (p *StarknetProvider) Configure(ctx context.Context, req provider.Configure
 func (p *StarknetProvider) Resources(ctx context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
 		NewDeclareContractTxResource,
+		NewDeployContractTxResource,
 	}
 }
 
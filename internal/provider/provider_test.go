@@ -0,0 +1,109 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestAccountEnvVar(t *testing.T) {
+	tests := []struct {
+		name    string
+		account string
+		envVar  string
+		want    string
+	}{
+		{name: "default account", envVar: PrivateKeyEnvVar, want: "STARKNET_PRIVATE_KEY"},
+		{name: "named account", account: "deployer", envVar: PrivateKeyEnvVar, want: "STARKNET_ACCOUNT_DEPLOYER_PRIVATE_KEY"},
+		{name: "normalized name", account: "ops-Team.1", envVar: KeystorePasswordEnvVar, want: "STARKNET_ACCOUNT_OPS_TEAM_1_KEYSTORE_PASSWORD"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := accountEnvVar(tt.account, tt.envVar); got != tt.want {
+				t.Errorf("accountEnvVar() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyEnvironment(t *testing.T) {
+	t.Setenv(RpcEndpointEnvVar, "http://env:9545")
+	t.Setenv(RpcEndpointsEnvVar, "http://a:9545, http://b:9545,")
+	t.Setenv(HeadersEnvVar, `{"x-api-key": "secret"}`)
+	t.Setenv(MaxRetriesEnvVar, "7")
+	t.Setenv(RequestsPerSecondEnvVar, "2.5")
+	t.Setenv(CompressRequestsEnvVar, "true")
+	t.Setenv(RetryMinBackoffEnvVar, "1s")
+	t.Setenv(KeystorePasswordEnvVar, "default-password")
+	t.Setenv("STARKNET_ACCOUNT_DEPLOYER_KEYSTORE_PASSWORD", "deployer-password")
+	t.Setenv(WaitForEnvVar, "")
+
+	data := StarknetProviderModel{
+		RetryMinBackoff: types.StringValue("2s"),
+		Accounts: []NamedAccountModel{
+			{Name: types.StringValue("deployer")},
+			{Name: types.StringValue("other")},
+		},
+	}
+	if diags := data.applyEnvironment(); diags.HasError() {
+		t.Fatalf("applyEnvironment() = %v", diags)
+	}
+
+	if got := data.RpcEndpoint.ValueString(); got != "http://env:9545" {
+		t.Errorf("rpc_endpoint = %q", got)
+	}
+	if got := data.RpcEndpoints.Elements(); len(got) != 2 || got[1] != types.StringValue("http://b:9545") {
+		t.Errorf("rpc_endpoints = %v", got)
+	}
+	if got := data.Headers.Elements()["x-api-key"]; got != types.StringValue("secret") {
+		t.Errorf("headers = %v", data.Headers)
+	}
+	if got := data.MaxRetries.ValueInt64(); got != 7 {
+		t.Errorf("max_retries = %d", got)
+	}
+	if got := data.RequestsPerSecond.ValueFloat64(); got != 2.5 {
+		t.Errorf("requests_per_second = %v", got)
+	}
+	if !data.CompressRequests.ValueBool() {
+		t.Errorf("compress_requests = %v", data.CompressRequests)
+	}
+	if got := data.RetryMinBackoff.ValueString(); got != "2s" {
+		t.Errorf("retry_min_backoff = %q, configuration must take precedence", got)
+	}
+	if !data.WaitFor.IsNull() {
+		t.Errorf("wait_for = %v, empty variables must be ignored", data.WaitFor)
+	}
+	if got := data.KeystorePassword.ValueString(); got != "default-password" {
+		t.Errorf("keystore_password = %q", got)
+	}
+	if got := data.Accounts[0].KeystorePassword.ValueString(); got != "deployer-password" {
+		t.Errorf("deployer keystore_password = %q", got)
+	}
+	if !data.Accounts[1].KeystorePassword.IsNull() {
+		t.Errorf("other keystore_password = %v, default account variables must not leak", data.Accounts[1].KeystorePassword)
+	}
+}
+
+func TestApplyEnvironmentInvalid(t *testing.T) {
+	tests := []struct {
+		envVar string
+		value  string
+	}{
+		{envVar: MaxRetriesEnvVar, value: "three"},
+		{envVar: RequestsPerSecondEnvVar, value: "fast"},
+		{envVar: CompressRequestsEnvVar, value: "maybe"},
+		{envVar: HeadersEnvVar, value: "x-api-key=secret"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.envVar, func(t *testing.T) {
+			t.Setenv(tt.envVar, tt.value)
+
+			data := StarknetProviderModel{}
+			if diags := data.applyEnvironment(); !diags.HasError() {
+				t.Errorf("applyEnvironment() = %v, want error", diags)
+			}
+		})
+	}
+}
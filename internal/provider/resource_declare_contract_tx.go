@@ -6,16 +6,18 @@ package provider
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
-	"strings"
-	"time"
 
-	"github.com/NethermindEth/starknet.go/account"
+	"github.com/NethermindEth/juno/core/felt"
 	"github.com/NethermindEth/starknet.go/contracts"
 	"github.com/NethermindEth/starknet.go/hash"
 	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
@@ -24,13 +26,13 @@ import (
 	framework_types "github.com/hashicorp/terraform-plugin-framework/types"
 
 	"github.com/hashicorp/terraform-plugin-log/tflog"
-
-	"github.com/baitcode/terraform-provider-starknet/internal/provider/types"
 )
 
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &DeclareContractTx{}
 var _ resource.ResourceWithImportState = &DeclareContractTx{}
+var _ resource.ResourceWithModifyPlan = &DeclareContractTx{}
+var _ resource.ResourceWithValidateConfig = &DeclareContractTx{}
 
 func NewDeclareContractTxResource() resource.Resource {
 	return &DeclareContractTx{}
@@ -38,10 +40,13 @@ func NewDeclareContractTxResource() resource.Resource {
 
 // DeclareContractTx defines the resource implementation.
 type DeclareContractTx struct {
-	client        *rpc.Provider
-	senderAddress types.Felt
-	keyStore      *account.MemKeystore
-	publicKey     string
+	client  *rpc.Provider
+	signers Signers
+	nonces  *NonceManager
+	chainId *felt.Felt
+
+	transactionVersion rpc.TransactionVersion
+	waitOptions        WaitOptions
 }
 
 // DeclareContractTxDataSource describes the resource data model.
@@ -49,6 +54,18 @@ type DeclareContractTxDataSource struct {
 	Casm      framework_types.String `tfsdk:"compiled_casm"`
 	File      framework_types.String `tfsdk:"compiled_class"`
 	ClassHash framework_types.String `tfsdk:"class_hash"`
+
+	ScarbProject framework_types.String `tfsdk:"scarb_project"`
+	ScarbProfile framework_types.String `tfsdk:"scarb_profile"`
+	ContractName framework_types.String `tfsdk:"contract_name"`
+
+	CompiledClassHash framework_types.String `tfsdk:"compiled_class_hash"`
+
+	TransactionSettingsModel
+	TransactionWaitModel
+	TransactionReceiptModel
+
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
 }
 
 func (r *DeclareContractTx) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -57,24 +74,43 @@ func (r *DeclareContractTx) Metadata(ctx context.Context, req resource.MetadataR
 
 func (r *DeclareContractTx) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
-		// This description is used by the documentation generator and the language server.
-		MarkdownDescription: "Example resource",
+		MarkdownDescription: "Declares a Sierra contract class with its compiled casm class.\n\n" +
+			"The class files are set with `compiled_class` and `compiled_casm`, or resolved from the Scarb build artifacts " +
+			"of `scarb_project` and `contract_name`. " +
+			"Declared classes can't be removed from the chain, destroying the resource only removes it from the state. " +
+			"Existing classes are imported by class hash, e.g. `terraform import starknet_declare_contract_tx.example 0x123`.",
 
-		Attributes: map[string]schema.Attribute{
+		Attributes: withTransactionReceipt(withTransactionWait(withTransactionSettings(map[string]schema.Attribute{
 			"compiled_casm": schema.StringAttribute{
-				Required:            true,
-				MarkdownDescription: "Contract casm class path",
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Contract casm class path. Resolved from `scarb_project` when omitted.",
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
 			"compiled_class": schema.StringAttribute{
-				Required:            true,
-				MarkdownDescription: "Contract file class path",
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Contract file class path. Resolved from `scarb_project` when omitted.",
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
+			"scarb_project": schema.StringAttribute{
+				Optional: true,
+				MarkdownDescription: "Scarb project directory. Artifacts are taken from the `target/<scarb_profile>/*.starknet_artifacts.json` files " +
+					"written by `scarb build`.",
+			},
+			"scarb_profile": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Scarb build profile. Defaults to `dev`.",
+			},
+			"contract_name": schema.StringAttribute{
+				Optional: true,
+				MarkdownDescription: "Contract name or module path within the Scarb project. " +
+					"The module path, e.g. `my_package::MyContract`, is required when several packages have a contract of the same name.",
+			},
 			"class_hash": schema.StringAttribute{
 				Required:            false,
 				MarkdownDescription: "ClassHash for contract",
@@ -83,6 +119,19 @@ func (r *DeclareContractTx) Schema(ctx context.Context, req resource.SchemaReque
 				},
 				Computed: true,
 			},
+			"compiled_class_hash": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Compiled class hash of the casm class",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		}))),
+
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+			}),
 		},
 	}
 }
@@ -105,10 +154,245 @@ func (r *DeclareContractTx) Configure(ctx context.Context, req resource.Configur
 	}
 
 	r.client = data.client
-	r.senderAddress.Felt = data.address
-	r.publicKey = data.publicKey
+	r.signers = data.signers
+	r.nonces = data.nonces
+	r.chainId = data.chainId
+	r.transactionVersion = data.transactionVersion
+	r.waitOptions = data.waitOptions
+}
+
+// readContractClass reads Sierra contract class from the file.
+func readContractClass(path string) (*rpc.ContractClass, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var class rpc.ContractClass
+	err = json.Unmarshal(content, &class)
+	if err != nil {
+		return nil, err
+	}
+
+	return &class, nil
+}
+
+func (r *DeclareContractTx) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data DeclareContractTxDataSource
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(data.TransactionSettingsModel.Validate()...)
+
+	if !data.ScarbProject.IsNull() {
+		if data.ContractName.IsNull() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("contract_name"),
+				"Missing contract name",
+				"contract_name is required when scarb_project is set.",
+			)
+		}
+		if !data.File.IsNull() || !data.Casm.IsNull() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("scarb_project"),
+				"Conflicting artifact configuration",
+				"compiled_class and compiled_casm can't be set together with scarb_project.",
+			)
+		}
+		return
+	}
+
+	if !data.ContractName.IsNull() || !data.ScarbProfile.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("scarb_project"),
+			"Missing Scarb project",
+			"contract_name and scarb_profile require scarb_project.",
+		)
+	}
+
+	if data.File.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("compiled_class"),
+			"Missing compiled class",
+			"Either compiled_class and compiled_casm or scarb_project and contract_name must be set.",
+		)
+	}
+	if data.Casm.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("compiled_casm"),
+			"Missing compiled casm",
+			"Either compiled_class and compiled_casm or scarb_project and contract_name must be set.",
+		)
+	}
+}
+
+// ModifyPlan hashes the compiled artifacts so both hashes are known at plan
+// time. The class is declared again whenever any of the hashes changes.
+func (r *DeclareContractTx) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to do on destroy
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan DeclareContractTxDataSource
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Fail early when there is nothing to sign the transaction with
+	var signer *Signer
+	if req.State.Raw.IsNull() && r.client != nil && !plan.Account.IsUnknown() {
+		var diags diag.Diagnostics
+		signer, diags = r.signers.Get(plan.Account.ValueString())
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	if req.State.Raw.IsNull() {
+		addMainnetWarning(&resp.Diagnostics, r.chainId)
+	}
+
+	if !plan.ScarbProject.IsNull() {
+		if plan.ScarbProject.IsUnknown() || plan.ContractName.IsUnknown() || plan.ScarbProfile.IsUnknown() {
+			plan.File = framework_types.StringUnknown()
+			plan.Casm = framework_types.StringUnknown()
+			plan.ClassHash = framework_types.StringUnknown()
+			plan.CompiledClassHash = framework_types.StringUnknown()
+			resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+			return
+		}
+
+		profile := DefaultScarbProfile
+		if !plan.ScarbProfile.IsNull() {
+			profile = plan.ScarbProfile.ValueString()
+		}
+
+		sierraPath, casmPath, err := ResolveScarbArtifacts(
+			plan.ScarbProject.ValueString(),
+			profile,
+			plan.ContractName.ValueString(),
+		)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("scarb_project"),
+				"Can't resolve Scarb artifacts",
+				err.Error(),
+			)
+			return
+		}
+
+		plan.File = framework_types.StringValue(sierraPath)
+		plan.Casm = framework_types.StringValue(casmPath)
+	}
+
+	if plan.File.IsUnknown() || plan.Casm.IsUnknown() {
+		return
+	}
+
+	class, err := readContractClass(plan.File.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("compiled_class"),
+			"Can't read compiled class.",
+			fmt.Sprintf("Unable to compute class hash, got error: %s", err),
+		)
+		return
+	}
+
+	compiledCasm, err := contracts.UnmarshalCasmClass(plan.Casm.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("compiled_casm"),
+			"Invalid casm class",
+			fmt.Sprintf("Unable to compute compiled class hash, got error: %s", err),
+		)
+		return
+	}
+
+	classHash := hash.ClassHash(*class)
+	compiledClassHash := hash.CompiledClassHash(*compiledCasm)
+	plan.ClassHash = framework_types.StringValue(classHash.String())
+	plan.CompiledClassHash = framework_types.StringValue(compiledClassHash.String())
+
+	if signer != nil && plan.TransactionSettingsModel.IsKnown() {
+		resp.Diagnostics.Append(r.simulate(ctx, signer, &plan, class, classHash, compiledClassHash)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	if !req.State.Raw.IsNull() {
+		var state DeclareContractTxDataSource
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		// Hashes are missing from the state right after import
+		if !state.ClassHash.IsNull() && !state.ClassHash.Equal(plan.ClassHash) {
+			resp.RequiresReplace = append(resp.RequiresReplace, path.Root("class_hash"))
+		}
+		if !state.CompiledClassHash.IsNull() && !state.CompiledClassHash.Equal(plan.CompiledClassHash) {
+			resp.RequiresReplace = append(resp.RequiresReplace, path.Root("compiled_class_hash"))
+		}
+	}
+
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+}
+
+// simulate runs the declaration against the pending block and puts the
+// estimated fee into the plan. A failed simulation is only a warning, unless
+// the declaration reverts. Nothing is estimated for declared classes.
+func (r *DeclareContractTx) simulate(
+	ctx context.Context,
+	signer *Signer,
+	plan *DeclareContractTxDataSource,
+	class *rpc.ContractClass,
+	classHash *felt.Felt,
+	compiledClassHash *felt.Felt,
+) diag.Diagnostics {
+	settings, diags := plan.TxSettings(ctx, r.transactionVersion)
+	if diags.HasError() {
+		return diags
+	}
+
+	a, err := signer.NewAccount(r.client)
+	if err != nil {
+		diags.AddWarning("Can't simulate transaction", err.Error())
+		return diags
+	}
+
+	tx, err := BuildDeclareTransaction(ctx, a, class, classHash, compiledClassHash, settings)
+	if err != nil {
+		diags.AddWarning("Can't simulate transaction", err.Error())
+		return diags
+	}
+
+	fee, err := SimulateTransaction(ctx, r.client, tx)
+	if isAlreadyDeclaredError(err) {
+		return diags
+	}
+	var revertErr *SimulationRevertError
+	if errors.As(err, &revertErr) {
+		diags.AddError(
+			"Transaction would revert",
+			fmt.Sprintf("Simulation of the class declaration reverted.\n\n%s", revertErr.Detail()),
+		)
+		return diags
+	}
+	if err != nil {
+		diags.AddWarning("Can't simulate transaction", err.Error())
+		return diags
+	}
 
-	r.keyStore = data.keyStore
+	plan.SetEstimatedFee(fee)
+	return diags
 }
 
 type ExecutionErrorData struct {
@@ -126,12 +410,26 @@ func (r *DeclareContractTx) Create(ctx context.Context, req resource.CreateReque
 		return
 	}
 
-	compiledClassString, err := os.ReadFile(data.File.ValueString())
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Can't read compiled class.",
-			fmt.Sprintf("Unable to create contract, got error: %s", err),
-		)
+	data.ResolveEstimatedFee()
+
+	signer, diags := r.signers.Get(data.Account.ValueString())
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	createTimeout, diags := data.Timeouts.Create(ctx, DefaultCreateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	waitOptions, diags := data.WaitOptions(r.waitOptions)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
@@ -146,24 +444,26 @@ func (r *DeclareContractTx) Create(ctx context.Context, req resource.CreateReque
 
 	compClassHash := hash.CompiledClassHash(*compiledCasm)
 
-	tflog.Warn(ctx, fmt.Sprintf("created a resource %s", compClassHash))
-	var class rpc.ContractClass
-	err = json.Unmarshal(compiledClassString, &class)
+	tflog.Debug(ctx, "computed compiled class hash", map[string]interface{}{
+		"compiled_class_hash": compClassHash.String(),
+	})
+	class, err := readContractClass(data.File.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError(
-			fmt.Sprintf("Can't unmarshal compiled class: %s", err),
-			fmt.Sprintf("Class: %s", compiledClassString),
+			"Can't read compiled class.",
+			fmt.Sprintf("Unable to create contract, got error: %s", err),
 		)
 		return
 	}
-	classHash := hash.ClassHash(class)
+	classHash := hash.ClassHash(*class)
 
-	a, err := account.NewAccount(
-		r.client,
-		r.senderAddress.Felt,
-		r.publicKey,
-		r.keyStore, 1,
-	)
+	settings, diags := data.TxSettings(ctx, r.transactionVersion)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	a, err := signer.NewAccount(r.client)
 
 	if err != nil {
 		resp.Diagnostics.AddError(
@@ -174,53 +474,69 @@ func (r *DeclareContractTx) Create(ctx context.Context, req resource.CreateReque
 	}
 
 	alreadyDeclared := false
-	broadcastTx, err := SignAndEstimateDeclareTransaction(a, &class, classHash, compClassHash)
+	submitted, err := SubmitTransaction(ctx, r.nonces, a, settings, func(ctx context.Context, settings *TxSettings) (rpc.BroadcastTxn, error) {
+		return SignAndEstimateDeclareTransaction(ctx, a, class, classHash, compClassHash, settings)
+	})
 	if err != nil {
-
-		if rpcErr, ok := err.(*rpc.RPCError); ok {
-			if errData, ok := rpcErr.Data.(map[string]interface{}); ok && rpcErr.Code == 41 && strings.Contains(errData["execution_error"].(string), "is already declared") {
-				alreadyDeclared = true
-				data.ClassHash = framework_types.StringValue(classHash.String())
-			}
+		if isAlreadyDeclaredError(err) {
+			alreadyDeclared = true
+			data.ClassHash = framework_types.StringValue(classHash.String())
+			data.SetNoReceipt()
 		}
 		if !alreadyDeclared {
 			resp.Diagnostics.AddError(
-				"Can't sign and estimate transaction hash",
-				fmt.Sprintf("Failed with error: %s", err),
+				"Can't send transaction",
+				fmt.Sprintf("Unable to create contract, got error: %s", err),
 			)
 			return
 		}
 	}
 
 	if !alreadyDeclared {
-		response, err := a.SendTransaction(context.Background(), broadcastTx)
+		// Saved before waiting, and again as soon as a stuck transaction is
+		// replaced, so an interrupted apply resumes the latest transaction
+		// instead of sending another one.
+		savePending := func(submitted *SubmittedTransaction) {
+			resp.Diagnostics.Append(savePendingTransaction(ctx, resp.Private, NewPendingTransaction(submitted, signer, waitOptions))...)
+		}
+		savePending(submitted)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		data.ClassHash = framework_types.StringValue(classHash.String())
+		data.CompiledClassHash = framework_types.StringValue(compClassHash.String())
+
+		receipt, err := WaitForSubmittedTransaction(ctx, r.client, a, submitted, waitOptions, savePending)
 		if err != nil {
-			resp.Diagnostics.AddError(
-				"Can't send transaction",
-				fmt.Sprintf("Unable to create contract, got error: %s", err),
-			)
+			addStoppedWaiting(ctx, &resp.Diagnostics, submitted.TransactionHash.String(), err)
+			data.SetPendingReceipt(submitted.TransactionHash)
+			resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 			return
 		}
 
-		for {
-			block, err := a.WaitForTransactionReceipt(
-				context.Background(),
-				response.TransactionHash,
-				5*time.Second,
+		addReplacedWarning(&resp.Diagnostics, submitted)
+
+		if receipt != nil {
+			data.SetReceipt(receipt)
+			resp.Diagnostics.Append(clearPendingTransaction(ctx, resp.Private)...)
+		} else {
+			data.SetPendingReceipt(submitted.TransactionHash)
+		}
+
+		if err := RevertError(receipt); err != nil {
+			resp.Diagnostics.AddError(
+				"Transaction reverted",
+				fmt.Sprintf("Unable to declare contract, got error: %s", err),
 			)
-			if err != nil {
-				resp.Diagnostics.AddError(
-					"Transaction failed",
-					fmt.Sprintf("Unable to create contract, got error: %s", err),
-				)
-				return
-			}
-			if block.FinalityStatus == rpc.TxnFinalityStatusAcceptedOnL2 {
-				break
-			}
+			// Keep the transaction hash in state so the spent fee can be traced.
+			resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+			return
 		}
 	}
 
+	data.CompiledClassHash = framework_types.StringValue(compClassHash.String())
+
 	tflog.Trace(ctx, "created a resource")
 
 	// Save data into Terraform state
@@ -237,13 +553,64 @@ func (r *DeclareContractTx) Read(ctx context.Context, req resource.ReadRequest,
 		return
 	}
 
-	// If applicable, this is a great opportunity to initialize any necessary
-	// provider client data and make a call using it.
-	// httpResp, err := r.client.Do(httpReq)
-	// if err != nil {
-	//     resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read example, got error: %s", err))
-	//     return
-	// }
+	// Check on the transaction of an interrupted apply
+	receipt, pendingStatus, diags := checkPendingTransaction(ctx, r.client, r.nonces, resp.Private)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	switch pendingStatus {
+	case PendingWaiting:
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+		return
+	case PendingDropped:
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if receipt != nil {
+		data.SetReceipt(receipt)
+		if err := RevertError(receipt); err != nil {
+			resp.Diagnostics.AddWarning(
+				"Transaction reverted",
+				fmt.Sprintf("Class %s will be declared again: %s", data.ClassHash.ValueString(), err),
+			)
+			resp.State.RemoveResource(ctx)
+			return
+		}
+	}
+
+	if data.ClassHash.IsNull() || data.ClassHash.IsUnknown() {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	classHash, err := utils.HexToFelt(data.ClassHash.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid class hash",
+			fmt.Sprintf("Can't parse class hash %s, got error: %s", data.ClassHash.ValueString(), err),
+		)
+		return
+	}
+
+	_, err = r.client.Class(ctx, rpc.WithBlockTag("latest"), classHash)
+	if err != nil {
+		// The class is gone after a devnet restart or when the provider
+		// points to another chain, so it has to be declared again.
+		if rpcErr, ok := err.(*rpc.RPCError); ok && rpcErr.Code == rpc.ErrClassHashNotFound.Code {
+			tflog.Warn(ctx, "class is not declared, removing from state", map[string]interface{}{
+				"class_hash": classHash.String(),
+			})
+			resp.State.RemoveResource(ctx)
+			return
+		}
+
+		resp.Diagnostics.AddError(
+			"Client Error",
+			fmt.Sprintf("Error reading class %s: %s", classHash.String(), err),
+		)
+		return
+	}
 
 	// Save updated data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
@@ -259,13 +626,16 @@ func (r *DeclareContractTx) Update(ctx context.Context, req resource.UpdateReque
 		return
 	}
 
-	// If applicable, this is a great opportunity to initialize any necessary
-	// provider client data and make a call using it.
-	// httpResp, err := r.client.Do(httpReq)
-	// if err != nil {
-	//     resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update example, got error: %s", err))
-	//     return
-	// }
+	var state DeclareContractTxDataSource
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// No transaction is sent on update, receipt stays the same. It is
+	// empty for imported classes.
+	data.TransactionReceiptModel = state.TransactionReceiptModel
 
 	// Save updated data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
@@ -291,5 +661,39 @@ func (r *DeclareContractTx) Delete(ctx context.Context, req resource.DeleteReque
 }
 
 func (r *DeclareContractTx) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	classHash, err := utils.HexToFelt(req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid class hash",
+			fmt.Sprintf("Expected class hash as import identifier, got %q: %s", req.ID, err),
+		)
+		return
+	}
+
+	class, err := r.client.Class(ctx, rpc.WithBlockTag("latest"), classHash)
+	if err != nil {
+		if rpcErr, ok := err.(*rpc.RPCError); ok && rpcErr.Code == rpc.ErrClassHashNotFound.Code {
+			resp.Diagnostics.AddError(
+				"Class is not declared",
+				fmt.Sprintf("Class %s is not declared on the chain", classHash.String()),
+			)
+			return
+		}
+
+		resp.Diagnostics.AddError(
+			"Client Error",
+			fmt.Sprintf("Error reading class %s: %s", classHash.String(), err),
+		)
+		return
+	}
+
+	if _, ok := class.(*rpc.ContractClass); !ok {
+		resp.Diagnostics.AddError(
+			"Unsupported class",
+			fmt.Sprintf("Class %s is a Cairo 0 class, only Sierra classes can be imported", classHash.String()),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("class_hash"), classHash.String())...)
 }
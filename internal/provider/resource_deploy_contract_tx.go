@@ -1,10 +1,824 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
 package provider
 
-import "github.com/hashicorp/terraform-plugin-framework/resource"
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/contracts"
+	"github.com/NethermindEth/starknet.go/curve"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	framework_types "github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/baitcode/terraform-provider-starknet/internal/provider/types"
+)
 
+var (
+	// UniversalDeployerAddress is the address of the Universal Deployer Contract.
+	// It is the same on mainnet, sepolia and starknet-devnet.
+	UniversalDeployerAddress, _ = utils.HexToFelt("0x041a78e741e5af2fec34b695679bc6891742439f7afb8484ecd7766661ad02bf")
+
+	deployContractSelector   = utils.GetSelectorFromNameFelt("deployContract")
+	contractDeployedSelector = utils.GetSelectorFromNameFelt("ContractDeployed")
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &DeployContractTx{}
 var _ resource.ResourceWithImportState = &DeployContractTx{}
+var _ resource.ResourceWithModifyPlan = &DeployContractTx{}
+var _ resource.ResourceWithValidateConfig = &DeployContractTx{}
+
+// importedDeployment tells whether the prior state comes from an import,
+// salt can't be read from the chain and is required otherwise.
+func importedDeployment(ctx context.Context, state tfsdk.State) (bool, diag.Diagnostics) {
+	var salt types.Felt
+	diags := state.GetAttribute(ctx, path.Root("salt"), &salt)
+	return salt.IsNull(), diags
+}
+
+const requiresReplaceUnlessImportedDescription = "Changing the value deploys the contract again, " +
+	"unless it's set for the first time after import."
+
+// requiresReplaceUnlessImportedString and friends let the configuration set
+// the deployment inputs of an imported contract without deploying it again.
+func requiresReplaceUnlessImportedString() planmodifier.String {
+	return stringplanmodifier.RequiresReplaceIf(
+		func(ctx context.Context, req planmodifier.StringRequest, resp *stringplanmodifier.RequiresReplaceIfFuncResponse) {
+			imported, diags := importedDeployment(ctx, req.State)
+			resp.Diagnostics.Append(diags...)
+			resp.RequiresReplace = !imported
+		},
+		requiresReplaceUnlessImportedDescription,
+		requiresReplaceUnlessImportedDescription,
+	)
+}
+
+func requiresReplaceUnlessImportedBool() planmodifier.Bool {
+	return boolplanmodifier.RequiresReplaceIf(
+		func(ctx context.Context, req planmodifier.BoolRequest, resp *boolplanmodifier.RequiresReplaceIfFuncResponse) {
+			imported, diags := importedDeployment(ctx, req.State)
+			resp.Diagnostics.Append(diags...)
+			resp.RequiresReplace = !imported
+		},
+		requiresReplaceUnlessImportedDescription,
+		requiresReplaceUnlessImportedDescription,
+	)
+}
+
+func requiresReplaceUnlessImportedList() planmodifier.List {
+	return listplanmodifier.RequiresReplaceIf(
+		func(ctx context.Context, req planmodifier.ListRequest, resp *listplanmodifier.RequiresReplaceIfFuncResponse) {
+			imported, diags := importedDeployment(ctx, req.State)
+			resp.Diagnostics.Append(diags...)
+			resp.RequiresReplace = !imported
+		},
+		requiresReplaceUnlessImportedDescription,
+		requiresReplaceUnlessImportedDescription,
+	)
+}
 
 func NewDeployContractTxResource() resource.Resource {
-	return &DeclareContractTx{}
+	return &DeployContractTx{}
+}
+
+// DeployContractTx defines the resource implementation.
+type DeployContractTx struct {
+	client            *rpc.Provider
+	signers           Signers
+	nonces            *NonceManager
+	chainId           *felt.Felt
+	universalDeployer *felt.Felt
+
+	transactionVersion rpc.TransactionVersion
+	waitOptions        WaitOptions
+}
+
+// DeployContractTxDataSource describes the resource data model.
+type DeployContractTxDataSource struct {
+	ClassHash           types.Felt           `tfsdk:"class_hash"`
+	Salt                types.Felt           `tfsdk:"salt"`
+	Unique              framework_types.Bool `tfsdk:"unique"`
+	ConstructorCalldata framework_types.List `tfsdk:"constructor_calldata"`
+	ContractAddress     types.Felt           `tfsdk:"contract_address"`
+	CurrentClassHash    types.Felt           `tfsdk:"current_class_hash"`
+
+	TransactionSettingsModel
+	TransactionWaitModel
+	TransactionReceiptModel
+
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
+}
+
+func (r *DeployContractTx) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_deploy_contract_tx"
+}
+
+func (r *DeployContractTx) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Deploys a declared contract class through the Universal Deployer Contract.\n\n" +
+			"Existing contracts are imported by address, e.g. `terraform import starknet_deploy_contract_tx.example 0x123`. " +
+			"Import reads the current class of the contract, the deployment inputs can't be read from the chain. " +
+			"`class_hash`, `salt`, `unique` and `constructor_calldata` must be set to the values the contract was deployed with, " +
+			"the first plan checks they produce the imported address and adopts them without deploying again.",
+
+		Attributes: withTransactionReceipt(withTransactionWait(withTransactionSettings(map[string]schema.Attribute{
+			"class_hash": schema.StringAttribute{
+				CustomType:          types.FeltType{},
+				Required:            true,
+				MarkdownDescription: "ClassHash of the declared contract the contract is deployed with",
+				PlanModifiers: []planmodifier.String{
+					requiresReplaceUnlessImportedString(),
+				},
+			},
+			"salt": schema.StringAttribute{
+				CustomType:          types.FeltType{},
+				Required:            true,
+				MarkdownDescription: "Salt used to compute the contract address",
+				PlanModifiers: []planmodifier.String{
+					requiresReplaceUnlessImportedString(),
+				},
+			},
+			"unique": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+				MarkdownDescription: "Mix the deployer address into the salt, making the contract address unique to the deployer",
+				PlanModifiers: []planmodifier.Bool{
+					requiresReplaceUnlessImportedBool(),
+				},
+			},
+			"constructor_calldata": schema.ListAttribute{
+				ElementType:         types.FeltType{},
+				Optional:            true,
+				MarkdownDescription: "Constructor calldata serialized as a list of felts",
+				PlanModifiers: []planmodifier.List{
+					requiresReplaceUnlessImportedList(),
+				},
+			},
+			"contract_address": schema.StringAttribute{
+				CustomType:          types.FeltType{},
+				Computed:            true,
+				MarkdownDescription: "Address of the deployed contract",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"current_class_hash": schema.StringAttribute{
+				CustomType: types.FeltType{},
+				Computed:   true,
+				MarkdownDescription: "Class hash of the contract read from the chain on refresh. " +
+					"It differs from `class_hash` once the contract replaced its class.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		}))),
+
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+			}),
+		},
+	}
+}
+
+func (r *DeployContractTx) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*ProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = data.client
+	r.signers = data.signers
+	r.nonces = data.nonces
+	r.chainId = data.chainId
+	r.universalDeployer = data.universalDeployer
+	r.transactionVersion = data.transactionVersion
+	r.waitOptions = data.waitOptions
+}
+
+// precomputeDeployedContractAddress computes the address the Universal
+// Deployer assigns to the contract. Unique deployments mix the deployer
+// address into the salt and use the Universal Deployer as the deployer.
+func precomputeDeployedContractAddress(
+	universalDeployer *felt.Felt,
+	deployer *felt.Felt,
+	classHash *felt.Felt,
+	salt *felt.Felt,
+	unique bool,
+	constructorCalldata []*felt.Felt,
+) *felt.Felt {
+	if unique {
+		return contracts.PrecomputeAddress(
+			universalDeployer,
+			curve.Pedersen(deployer, salt),
+			classHash,
+			constructorCalldata,
+		)
+	}
+
+	return contracts.PrecomputeAddress(&felt.Zero, salt, classHash, constructorCalldata)
+}
+
+// ValidateConfig checks the transaction settings of the configuration.
+func (r *DeployContractTx) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data DeployContractTxDataSource
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(data.TransactionSettingsModel.Validate()...)
+}
+
+// deployContractCall builds the Universal Deployer call deploying the contract.
+func (r *DeployContractTx) deployContractCall(
+	classHash *felt.Felt,
+	salt *felt.Felt,
+	unique bool,
+	constructorCalldata []*felt.Felt,
+) rpc.FunctionCall {
+	uniqueFelt := &felt.Zero
+	if unique {
+		uniqueFelt = new(felt.Felt).SetUint64(1)
+	}
+
+	calldata := []*felt.Felt{
+		classHash,
+		salt,
+		uniqueFelt,
+		new(felt.Felt).SetUint64(uint64(len(constructorCalldata))),
+	}
+	calldata = append(calldata, constructorCalldata...)
+
+	return rpc.FunctionCall{
+		ContractAddress:    r.universalDeployer,
+		EntryPointSelector: deployContractSelector,
+		Calldata:           calldata,
+	}
+}
+
+// ModifyPlan fills in the contract address when all inputs are known at plan
+// time and simulates the deployment of a declared class to show its fee and
+// fail on revert.
+func (r *DeployContractTx) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan DeployContractTxDataSource
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Address only has to be planned on create, imported contracts only
+	// have their deployment inputs checked
+	if !req.State.Raw.IsNull() {
+		imported, diags := importedDeployment(ctx, req.State)
+		resp.Diagnostics.Append(diags...)
+		if imported && r.client != nil {
+			resp.Diagnostics.Append(r.checkImportedDeployment(ctx, plan)...)
+		}
+		return
+	}
+
+	// Provider isn't configured yet, e.g. its settings depend on other resources
+	if r.client == nil || plan.Account.IsUnknown() {
+		return
+	}
+
+	// Fail early when there is nothing to sign the transaction with
+	signer, diags := r.signers.Get(plan.Account.ValueString())
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	addMainnetWarning(&resp.Diagnostics, r.chainId)
+
+	if plan.ClassHash.IsUnknown() || plan.Salt.IsUnknown() || plan.Unique.IsUnknown() {
+		return
+	}
+
+	calldata, diags := constructorCalldata(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() || calldata == nil {
+		return
+	}
+
+	plan.ContractAddress.FromFelt(precomputeDeployedContractAddress(
+		r.universalDeployer,
+		signer.Address,
+		plan.ClassHash.Felt,
+		plan.Salt.Felt,
+		plan.Unique.ValueBool(),
+		calldata,
+	))
+
+	if plan.TransactionSettingsModel.IsKnown() {
+		deployCall := r.deployContractCall(plan.ClassHash.Felt, plan.Salt.Felt, plan.Unique.ValueBool(), calldata)
+		resp.Diagnostics.Append(r.simulate(ctx, signer, &plan, deployCall)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+}
+
+// constructorCalldata returns the calldata of the plan, nil when any of it
+// isn't known yet.
+func constructorCalldata(ctx context.Context, plan DeployContractTxDataSource) ([]*felt.Felt, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	if plan.ConstructorCalldata.IsUnknown() {
+		return nil, diags
+	}
+
+	var values []types.Felt
+	if !plan.ConstructorCalldata.IsNull() {
+		diags.Append(plan.ConstructorCalldata.ElementsAs(ctx, &values, false)...)
+		if diags.HasError() {
+			return nil, diags
+		}
+	}
+
+	calldata := []*felt.Felt{}
+	for _, value := range values {
+		if value.IsUnknown() || value.IsNull() {
+			return nil, diags
+		}
+		calldata = append(calldata, value.Felt)
+	}
+	return calldata, diags
+}
+
+// checkImportedDeployment checks the configured deployment inputs of an
+// imported contract produce its address, so they are adopted without
+// deploying the contract again.
+func (r *DeployContractTx) checkImportedDeployment(ctx context.Context, plan DeployContractTxDataSource) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if plan.Account.IsUnknown() || plan.ClassHash.IsUnknown() || plan.Salt.IsUnknown() || plan.Unique.IsUnknown() {
+		diags.AddError(
+			"Can't check imported contract",
+			"class_hash, salt, unique and constructor_calldata of an imported contract must be known at plan time.",
+		)
+		return diags
+	}
+	if plan.Salt.IsNull() {
+		diags.AddAttributeError(
+			path.Root("salt"),
+			"Missing deployment inputs",
+			"salt must be set to the value the imported contract was deployed with.",
+		)
+		return diags
+	}
+
+	calldata, calldataDiags := constructorCalldata(ctx, plan)
+	diags.Append(calldataDiags...)
+	if diags.HasError() {
+		return diags
+	}
+	if calldata == nil {
+		diags.AddAttributeError(
+			path.Root("constructor_calldata"),
+			"Can't check imported contract",
+			"constructor_calldata of an imported contract must be known at plan time.",
+		)
+		return diags
+	}
+
+	signer, signerDiags := r.signers.Get(plan.Account.ValueString())
+	diags.Append(signerDiags...)
+	if diags.HasError() {
+		return diags
+	}
+
+	address := precomputeDeployedContractAddress(
+		r.universalDeployer,
+		signer.Address,
+		plan.ClassHash.Felt,
+		plan.Salt.Felt,
+		plan.Unique.ValueBool(),
+		calldata,
+	)
+	if !address.Equal(plan.ContractAddress.Felt) {
+		diags.AddError(
+			"Configuration doesn't match imported contract",
+			fmt.Sprintf(
+				"class_hash, salt, unique, constructor_calldata and the deployer account produce address %s, "+
+					"the imported contract is %s. Set them to the values the contract was deployed with.",
+				address, plan.ContractAddress.String(),
+			),
+		)
+	}
+	return diags
+}
+
+// simulate runs the deployment against the pending block and puts the
+// estimated fee into the plan. A failed simulation is only a warning, unless
+// the deployment reverts.
+func (r *DeployContractTx) simulate(
+	ctx context.Context,
+	signer *Signer,
+	plan *DeployContractTxDataSource,
+	deployCall rpc.FunctionCall,
+) diag.Diagnostics {
+	settings, diags := plan.TxSettings(ctx, r.transactionVersion)
+	if diags.HasError() {
+		return diags
+	}
+
+	// A class declared in the same apply isn't on chain yet, the deployment
+	// would revert with an undeclared class
+	declared, err := isClassDeclared(ctx, r.client, plan.ClassHash.Felt)
+	if err != nil {
+		diags.AddWarning("Can't simulate transaction", err.Error())
+		return diags
+	}
+	if !declared {
+		tflog.Info(ctx, "class is not declared yet, skipping deployment simulation", map[string]interface{}{
+			"class_hash": plan.ClassHash.Felt.String(),
+		})
+		return diags
+	}
+
+	a, err := signer.NewAccount(r.client)
+	if err != nil {
+		diags.AddWarning("Can't simulate transaction", err.Error())
+		return diags
+	}
+
+	tx, err := BuildInvokeTransaction(ctx, a, []rpc.FunctionCall{deployCall}, settings)
+	if err != nil {
+		diags.AddWarning("Can't simulate transaction", err.Error())
+		return diags
+	}
+
+	fee, err := SimulateTransaction(ctx, r.client, tx)
+	var revertErr *SimulationRevertError
+	if errors.As(err, &revertErr) {
+		diags.AddError(
+			"Transaction would revert",
+			fmt.Sprintf("Simulation of the contract deployment reverted.\n\n%s", revertErr.Detail()),
+		)
+		return diags
+	}
+	if err != nil {
+		diags.AddWarning("Can't simulate transaction", err.Error())
+		return diags
+	}
+
+	plan.SetEstimatedFee(fee)
+	return diags
+}
+
+// findDeployedContractAddress looks up the ContractDeployed event emitted by
+// the Universal Deployer and returns the deployed contract address.
+func findDeployedContractAddress(universalDeployer *felt.Felt, receipt *rpc.TransactionReceiptWithBlockInfo) (*felt.Felt, error) {
+	for _, event := range receipt.Events {
+		if !event.FromAddress.Equal(universalDeployer) {
+			continue
+		}
+		if len(event.Keys) == 0 || !event.Keys[0].Equal(contractDeployedSelector) {
+			continue
+		}
+		if len(event.Data) == 0 {
+			return nil, fmt.Errorf("ContractDeployed event has no data")
+		}
+		// data: address, deployer, unique, class_hash, calldata_len, calldata..., salt
+		return event.Data[0], nil
+	}
+
+	return nil, fmt.Errorf("ContractDeployed event not found in transaction %s", receipt.TransactionHash)
+}
+
+func (r *DeployContractTx) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data DeployContractTxDataSource
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.ResolveEstimatedFee()
+	data.CurrentClassHash = data.ClassHash
+
+	signer, diags := r.signers.Get(data.Account.ValueString())
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	createTimeout, diags := data.Timeouts.Create(ctx, DefaultCreateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	waitOptions, diags := data.WaitOptions(r.waitOptions)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var constructorCalldata []types.Felt
+	if !data.ConstructorCalldata.IsNull() {
+		resp.Diagnostics.Append(data.ConstructorCalldata.ElementsAs(ctx, &constructorCalldata, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	calldata := []*felt.Felt{}
+	for _, arg := range constructorCalldata {
+		calldata = append(calldata, arg.Felt)
+	}
+	deployCall := r.deployContractCall(data.ClassHash.Felt, data.Salt.Felt, data.Unique.ValueBool(), calldata)
+
+	settings, diags := data.TxSettings(ctx, r.transactionVersion)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	a, err := signer.NewAccount(r.client)
+
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Can't create account",
+			fmt.Sprintf("Unable to deploy contract, got error: %s", err),
+		)
+		return
+	}
+
+	submitted, err := SubmitTransaction(ctx, r.nonces, a, settings, func(ctx context.Context, settings *TxSettings) (rpc.BroadcastTxn, error) {
+		return SignAndEstimateInvokeTransaction(ctx, a, []rpc.FunctionCall{deployCall}, settings)
+	})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Can't send transaction",
+			fmt.Sprintf("Unable to deploy contract, got error: %s", err),
+		)
+		return
+	}
+
+	// Saved before waiting, and again as soon as a stuck transaction is
+	// replaced, so an interrupted apply resumes the latest transaction
+	// instead of sending another one.
+	savePending := func(submitted *SubmittedTransaction) {
+		resp.Diagnostics.Append(savePendingTransaction(ctx, resp.Private, NewPendingTransaction(submitted, signer, waitOptions))...)
+	}
+	savePending(submitted)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Without a receipt the address is computed the same way the Universal
+	// Deployer does, otherwise it's taken from the deployment event.
+	contractAddress := precomputeDeployedContractAddress(
+		r.universalDeployer,
+		signer.Address,
+		data.ClassHash.Felt,
+		data.Salt.Felt,
+		data.Unique.ValueBool(),
+		calldata,
+	)
+
+	receipt, err := WaitForSubmittedTransaction(ctx, r.client, a, submitted, waitOptions, savePending)
+	if err != nil {
+		addStoppedWaiting(ctx, &resp.Diagnostics, submitted.TransactionHash.String(), err)
+		data.SetPendingReceipt(submitted.TransactionHash)
+		data.ContractAddress.FromFelt(contractAddress)
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+		return
+	}
+
+	addReplacedWarning(&resp.Diagnostics, submitted)
+
+	if receipt != nil {
+		data.SetReceipt(receipt)
+		resp.Diagnostics.Append(clearPendingTransaction(ctx, resp.Private)...)
+	} else {
+		data.SetPendingReceipt(submitted.TransactionHash)
+	}
+
+	if err := RevertError(receipt); err != nil {
+		resp.Diagnostics.AddError(
+			"Transaction reverted",
+			fmt.Sprintf("Unable to deploy contract, got error: %s", err),
+		)
+		// Keep the transaction hash in state so the spent fee can be traced.
+		if data.ContractAddress.IsUnknown() {
+			data.ContractAddress = types.NewFeltNull()
+		}
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+		return
+	}
+
+	if receipt != nil {
+		contractAddress, err = findDeployedContractAddress(r.universalDeployer, receipt)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Can't find deployed contract address",
+				err.Error(),
+			)
+			return
+		}
+	}
+
+	data.ContractAddress.FromFelt(contractAddress)
+
+	tflog.Trace(ctx, "deployed a contract", map[string]interface{}{
+		"contract_address": contractAddress.String(),
+	})
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *DeployContractTx) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data DeployContractTxDataSource
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Check on the transaction of an interrupted apply
+	receipt, pendingStatus, diags := checkPendingTransaction(ctx, r.client, r.nonces, resp.Private)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	switch pendingStatus {
+	case PendingWaiting:
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+		return
+	case PendingDropped:
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if receipt != nil {
+		data.SetReceipt(receipt)
+		if err := RevertError(receipt); err != nil {
+			resp.Diagnostics.AddWarning(
+				"Transaction reverted",
+				fmt.Sprintf("Contract %s will be deployed again: %s", data.ContractAddress.String(), err),
+			)
+			resp.State.RemoveResource(ctx)
+			return
+		}
+
+		contractAddress, err := findDeployedContractAddress(r.universalDeployer, receipt)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Can't find deployed contract address",
+				err.Error(),
+			)
+			return
+		}
+		data.ContractAddress.FromFelt(contractAddress)
+	}
+
+	// A reverted deployment whose address wasn't known at plan time has
+	// nothing to read, it's deployed again
+	if data.ContractAddress.IsNull() {
+		tflog.Warn(ctx, "contract was not deployed, removing from state", map[string]interface{}{
+			"transaction_hash": data.TransactionHash.ValueString(),
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	classHash, err := r.client.ClassHashAt(
+		ctx,
+		rpc.WithBlockTag("latest"),
+		data.ContractAddress.Felt,
+	)
+	if err != nil {
+		if rpcErr, ok := err.(*rpc.RPCError); ok && rpcErr.Code == rpc.ErrContractNotFound.Code {
+			tflog.Warn(ctx, "contract not found, removing from state", map[string]interface{}{
+				"contract_address": data.ContractAddress.String(),
+			})
+			resp.State.RemoveResource(ctx)
+			return
+		}
+
+		resp.Diagnostics.AddError(
+			"Client Error",
+			fmt.Sprintf("Error reading contract %s class hash: %s", data.ContractAddress.String(), err),
+		)
+		return
+	}
+
+	data.CurrentClassHash.FromFelt(classHash)
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *DeployContractTx) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data DeployContractTxDataSource
+
+	// Every configurable attribute requires replacement, so only computed
+	// values are carried over here.
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var state DeployContractTxDataSource
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.TransactionReceiptModel = state.TransactionReceiptModel
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *DeployContractTx) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data DeployContractTxDataSource
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Deployed contracts can't be removed from the chain, the resource is
+	// only dropped from the state.
+}
+
+// ImportState imports a contract by address. Only the address and the class
+// can be read from the chain, checkImportedDeployment adopts the rest from
+// the configuration.
+func (r *DeployContractTx) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	contractAddress, err := utils.HexToFelt(req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid import ID",
+			fmt.Sprintf("Expected contract address, got %q: %s", req.ID, err),
+		)
+		return
+	}
+
+	classHash, err := r.client.ClassHashAt(ctx, rpc.WithBlockTag("latest"), contractAddress)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Can't import contract",
+			fmt.Sprintf("Error reading contract %s class hash: %s", contractAddress, err),
+		)
+		return
+	}
+
+	var address, class types.Felt
+	address.FromFelt(contractAddress)
+	class.FromFelt(classHash)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("contract_address"), address)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("class_hash"), class)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("current_class_hash"), class)...)
 }
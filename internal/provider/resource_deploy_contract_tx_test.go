@@ -0,0 +1,189 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func hexFelts(t *testing.T, values ...string) []*felt.Felt {
+	t.Helper()
+
+	felts := []*felt.Felt{}
+	for _, value := range values {
+		felts = append(felts, utils.TestHexToFelt(t, value))
+	}
+	return felts
+}
+
+// ContractDeployed events of the Universal Deployer: address, deployer,
+// unique, class_hash, calldata_len, calldata..., salt.
+var contractDeployedVectors = []struct {
+	name string
+	data []string
+}{
+	{
+		// Mainnet block 19199, transaction 0x5c72ea25140d62e2dc7090f369366d8c8f39e7192662361797035a9e0866c8
+		name: "not unique",
+		data: []string{
+			"0x39b4d62e4c59d31d1b18e70bf34025ab76510bc42f1357e6211d7c7a8ada59d",
+			"0x135faa783a11cee068cf6424db10f59f252941c4067c243495c7b76ea327b60",
+			"0x0",
+			"0x1ffa341ccd458abc28b46d41d09bcdf69fc7e351a7cef42e63975ca997e6a58",
+			"0x5",
+			"0x6d706cfbac9b8262d601c38251c5fbe0497c3a96cc91a92b08d91b61d9e70c4",
+			"0x79dc0da7c54b95f10aa182ad0a46400db63156920adb65eca2654c0945a463",
+			"0x2",
+			"0x4a0ac93c16a6dc5bf6a4722db8fb75e181aae6aa14ab417f3d8745bc120887f",
+			"0x6b648b36b074a91eee55730f5f5e075ec19c0a8f9ffb0903cefeee93b6ff328",
+			"0x309b1a78da270970e6d699ca738ce26ca41bc075fb8446c7d511b267bfe933",
+		},
+	},
+	{
+		// Sepolia block 64159
+		name: "unique",
+		data: []string{
+			"0x20b3f28573bb2882a0555a63ae4dc35296f53e214c8be816117951d7953598c",
+			"0x1bef1d205c047ee5d24ed873f1fa8c6c6cc37d0d3808266e2fe9a64b6ff9474",
+			"0x1",
+			"0x47b774d6ee3573805f590bc556f500022d6d8f2b01a741239ff93ca22e6dddb",
+			"0x1",
+			"0x3",
+			"0x7e3c89140da097d5e30b51d617ea9ed4e8bbb0a9172e8a9359910d43dba9daa",
+		},
+	},
+	{
+		// Sepolia block 0x42a4c6a4c3dffee2cce78f04259b499437049b0084c3296da9fbbec7eda79b2
+		name: "unique without calldata",
+		data: []string{
+			"0x7cd1359ded810b3cd2dfb37121fff6423cda9384c3fc1052e54bf801fe434e4",
+			"0x69fdcf2381d323ddef117be3888f279ac750f5b1f51e6fd674a5053103c2bb7",
+			"0x1",
+			"0x13267b9ab398ff17b9260d91fb21e8167bb6bf4aca757152eec723237858dff",
+			"0x0",
+			"0x28c02fad1fa5d5dc07d8f17be5335891fba60179e0a80cc98005af9ebcfe939",
+		},
+	},
+}
+
+func TestPrecomputeDeployedContractAddress(t *testing.T) {
+	for _, tt := range contractDeployedVectors {
+		t.Run(tt.name, func(t *testing.T) {
+			data := hexFelts(t, tt.data...)
+			address, deployer, unique, classHash := data[0], data[1], data[2], data[3]
+			calldata := data[5 : len(data)-1]
+			salt := data[len(data)-1]
+
+			got := precomputeDeployedContractAddress(
+				UniversalDeployerAddress,
+				deployer,
+				classHash,
+				salt,
+				unique.IsOne(),
+				calldata,
+			)
+			if !got.Equal(address) {
+				t.Errorf("precomputeDeployedContractAddress() = %s, want %s", got, address)
+			}
+		})
+	}
+}
+
+func TestFindDeployedContractAddress(t *testing.T) {
+	vector := hexFelts(t, contractDeployedVectors[1].data...)
+	otherContract := utils.TestHexToFelt(t, "0x123")
+	deployedEvent := func(from *felt.Felt, data []*felt.Felt) rpc.Event {
+		return rpc.Event{
+			FromAddress: from,
+			Keys:        []*felt.Felt{contractDeployedSelector},
+			Data:        data,
+		}
+	}
+
+	tests := []struct {
+		name    string
+		events  []rpc.Event
+		want    *felt.Felt
+		wantErr bool
+	}{
+		{
+			name: "deployed",
+			events: []rpc.Event{
+				{FromAddress: otherContract, Keys: []*felt.Felt{otherContract}},
+				deployedEvent(UniversalDeployerAddress, vector),
+			},
+			want: vector[0],
+		},
+		{
+			name:    "event of another contract",
+			events:  []rpc.Event{deployedEvent(otherContract, vector)},
+			wantErr: true,
+		},
+		{
+			name:    "event without data",
+			events:  []rpc.Event{deployedEvent(UniversalDeployerAddress, nil)},
+			wantErr: true,
+		},
+		{
+			name:    "no events",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			receipt := &rpc.TransactionReceiptWithBlockInfo{TransactionReceipt: rpc.TransactionReceipt{
+				TransactionHash: otherContract,
+				Events:          tt.events,
+			}}
+
+			got, err := findDeployedContractAddress(UniversalDeployerAddress, receipt)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("findDeployedContractAddress() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && !got.Equal(tt.want) {
+				t.Errorf("findDeployedContractAddress() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeployContractTxReadReverted(t *testing.T) {
+	ctx := context.Background()
+
+	// Without a client any RPC call panics, the contract must not be read
+	r := &DeployContractTx{}
+	var schemaResp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+
+	// State of a deployment that reverted before its address was known
+	state := tfsdk.State{
+		Schema: schemaResp.Schema,
+		Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil),
+	}
+	for attribute, value := range map[string]string{
+		"class_hash":       "0x47b774d6ee3573805f590bc556f500022d6d8f2b01a741239ff93ca22e6dddb",
+		"transaction_hash": "0xabc",
+		"execution_status": string(rpc.TxnExecutionStatusREVERTED),
+	} {
+		if diags := state.SetAttribute(ctx, path.Root(attribute), value); diags.HasError() {
+			t.Fatalf("SetAttribute(%s) = %v", attribute, diags)
+		}
+	}
+
+	resp := resource.ReadResponse{State: state}
+	r.Read(ctx, resource.ReadRequest{State: state}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Read() = %v", resp.Diagnostics)
+	}
+	if !resp.State.Raw.IsNull() {
+		t.Errorf("Read() state = %v, want resource removed", resp.State.Raw)
+	}
+}
@@ -0,0 +1,274 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NethermindEth/starknet.go/rpc"
+	ethrpc "github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	DefaultMaxBlockLag         = 10
+	DefaultHealthCheckInterval = time.Minute
+
+	// unhealthyPeriod is how long a failed endpoint is skipped before it's
+	// tried again.
+	unhealthyPeriod = 30 * time.Second
+)
+
+// EndpointHealth is the result of an endpoint health check.
+type EndpointHealth struct {
+	URL         string
+	ChainId     string
+	SpecVersion string
+	BlockNumber uint64
+	Err         error
+}
+
+type endpointState struct {
+	url            *url.URL
+	unhealthyUntil time.Time
+	lagging        bool
+}
+
+// FailoverTransport sends requests to the first healthy endpoint and fails
+// over to the next one when an endpoint errors. Endpoints lagging behind the
+// others in block height are only used when no other endpoint is available.
+type FailoverTransport struct {
+	Base                http.RoundTripper
+	MaxBlockLag         uint64
+	HealthCheckInterval time.Duration
+
+	mu          sync.Mutex
+	endpoints   []*endpointState
+	lastCheck   time.Time
+	checkActive bool
+}
+
+func NewFailoverTransport(base http.RoundTripper, endpoints []string) (*FailoverTransport, error) {
+	if len(endpoints) == 0 {
+		return nil, fmt.Errorf("no rpc endpoints configured")
+	}
+
+	t := &FailoverTransport{
+		Base:                base,
+		MaxBlockLag:         DefaultMaxBlockLag,
+		HealthCheckInterval: DefaultHealthCheckInterval,
+	}
+	for _, endpoint := range endpoints {
+		parsed, err := url.Parse(endpoint)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rpc endpoint %q: %w", endpoint, err)
+		}
+		if parsed.Scheme != "http" && parsed.Scheme != "https" {
+			return nil, fmt.Errorf("invalid rpc endpoint %q: only http and https are supported", endpoint)
+		}
+		t.endpoints = append(t.endpoints, &endpointState{url: parsed})
+	}
+	return t, nil
+}
+
+// candidates returns endpoints in the order they should be tried: healthy
+// ones first, then lagging ones, then the ones that recently failed.
+func (t *FailoverTransport) candidates() []*endpointState {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	healthy, lagging, unhealthy := []*endpointState{}, []*endpointState{}, []*endpointState{}
+	for _, endpoint := range t.endpoints {
+		switch {
+		case endpoint.unhealthyUntil.After(now):
+			unhealthy = append(unhealthy, endpoint)
+		case endpoint.lagging:
+			lagging = append(lagging, endpoint)
+		default:
+			healthy = append(healthy, endpoint)
+		}
+	}
+	return append(append(healthy, lagging...), unhealthy...)
+}
+
+func (t *FailoverTransport) markUnhealthy(endpoint *endpointState) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	endpoint.unhealthyUntil = time.Now().Add(unhealthyPeriod)
+}
+
+func (t *FailoverTransport) markHealthy(endpoint *endpointState) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	endpoint.unhealthyUntil = time.Time{}
+}
+
+// maybeRecheck runs a background health check when the last one is older
+// than HealthCheckInterval, so lagging endpoints are noticed during long
+// applies.
+func (t *FailoverTransport) maybeRecheck() {
+	if len(t.endpoints) < 2 || t.HealthCheckInterval <= 0 {
+		return
+	}
+
+	t.mu.Lock()
+	if t.checkActive || time.Since(t.lastCheck) < t.HealthCheckInterval {
+		t.mu.Unlock()
+		return
+	}
+	t.checkActive = true
+	t.mu.Unlock()
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		t.CheckHealth(ctx)
+	}()
+}
+
+func (t *FailoverTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.maybeRecheck()
+
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Transactions are sent to a single endpoint, resending them to another
+	// one could hide the original result.
+	candidates := t.candidates()
+	if !isIdempotent(body) {
+		candidates = candidates[:1]
+	}
+
+	var resp *http.Response
+	var err error
+	for i, endpoint := range candidates {
+		attempt := req.Clone(req.Context())
+		attempt.URL = endpoint.url
+		attempt.Host = ""
+		if body != nil {
+			attempt.Body = io.NopCloser(bytes.NewReader(body))
+			attempt.GetBody = func() (io.ReadCloser, error) {
+				return io.NopCloser(bytes.NewReader(body)), nil
+			}
+		}
+
+		resp, err = t.Base.RoundTrip(attempt)
+		if req.Context().Err() != nil {
+			return resp, err
+		}
+		if err == nil && !isRetryableStatus(resp.StatusCode) {
+			t.markHealthy(endpoint)
+			return resp, nil
+		}
+
+		t.markUnhealthy(endpoint)
+		if i < len(candidates)-1 && resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+	}
+	return resp, err
+}
+
+// CheckHealth queries chain ID, spec version and block number of every
+// endpoint, marks failing and lagging endpoints and returns the results.
+func (t *FailoverTransport) CheckHealth(ctx context.Context) []EndpointHealth {
+	results := make([]EndpointHealth, len(t.endpoints))
+
+	var wg sync.WaitGroup
+	for i, endpoint := range t.endpoints {
+		wg.Add(1)
+		go func(i int, endpoint *endpointState) {
+			defer wg.Done()
+			results[i] = t.checkEndpoint(ctx, endpoint.url.String())
+		}(i, endpoint)
+	}
+	wg.Wait()
+
+	var maxBlockNumber uint64
+	for _, result := range results {
+		if result.Err == nil && result.BlockNumber > maxBlockNumber {
+			maxBlockNumber = result.BlockNumber
+		}
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	for i, endpoint := range t.endpoints {
+		if results[i].Err != nil {
+			endpoint.unhealthyUntil = now.Add(unhealthyPeriod)
+			continue
+		}
+		endpoint.unhealthyUntil = time.Time{}
+		endpoint.lagging = results[i].BlockNumber+t.MaxBlockLag < maxBlockNumber
+	}
+	t.lastCheck = now
+	t.checkActive = false
+
+	return results
+}
+
+func (t *FailoverTransport) checkEndpoint(ctx context.Context, endpoint string) EndpointHealth {
+	result := EndpointHealth{URL: endpoint}
+
+	client, err := rpc.NewProvider(endpoint, ethrpc.WithHTTPClient(&http.Client{Transport: t.Base}))
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	result.ChainId, result.Err = client.ChainID(ctx)
+	if result.Err != nil {
+		return result
+	}
+	result.SpecVersion, result.Err = client.SpecVersion(ctx)
+	if result.Err != nil {
+		return result
+	}
+	result.BlockNumber, result.Err = client.BlockNumber(ctx)
+	return result
+}
+
+// checkEndpointsAgree verifies the reachable endpoints serve the same chain.
+// It returns the chain ID and the errors of unreachable endpoints, failing
+// when none of the endpoints is reachable.
+func checkEndpointsAgree(results []EndpointHealth) (string, []string, error) {
+	chainId, chainIdURL := "", ""
+	failures := []string{}
+	for _, result := range results {
+		if result.Err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %s", result.URL, result.Err))
+			continue
+		}
+		if chainIdURL == "" {
+			chainId, chainIdURL = result.ChainId, result.URL
+			continue
+		}
+		if result.ChainId != chainId {
+			return "", failures, fmt.Errorf(
+				"rpc endpoints serve different chains: %s serves %s, %s serves %s",
+				chainIdURL, chainId, result.URL, result.ChainId,
+			)
+		}
+	}
+
+	if chainIdURL == "" {
+		return "", failures, fmt.Errorf("none of the rpc endpoints is reachable: %s", strings.Join(failures, "; "))
+	}
+	return chainId, failures, nil
+}
@@ -0,0 +1,208 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// fakeNode is a JSON-RPC endpoint answering the health check methods.
+type fakeNode struct {
+	*httptest.Server
+
+	chainId     string
+	blockNumber uint64
+	// status is answered instead of a result when set
+	status atomic.Int32
+	calls  atomic.Int32
+}
+
+func newFakeNode(t *testing.T, chainId string, blockNumber uint64) *fakeNode {
+	t.Helper()
+
+	node := &fakeNode{chainId: chainId, blockNumber: blockNumber}
+	node.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		node.calls.Add(1)
+		if status := node.status.Load(); status != 0 {
+			w.WriteHeader(int(status))
+			return
+		}
+
+		var request struct {
+			Id     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		var result interface{}
+		switch request.Method {
+		case "starknet_chainId":
+			result = node.chainId
+		case "starknet_specVersion":
+			result = "0.7.1"
+		case "starknet_blockNumber":
+			result = node.blockNumber
+		default:
+			result = "0x0"
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": request.Id, "result": result})
+	}))
+	t.Cleanup(node.Close)
+	return node
+}
+
+func newTestFailoverTransport(t *testing.T, nodes ...*fakeNode) *FailoverTransport {
+	t.Helper()
+
+	endpoints := []string{}
+	for _, node := range nodes {
+		endpoints = append(endpoints, node.URL)
+	}
+	transport, err := NewFailoverTransport(http.DefaultTransport, endpoints)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Health checks are run explicitly by the tests
+	transport.HealthCheckInterval = 0
+	return transport
+}
+
+func postRpc(t *testing.T, transport http.RoundTripper, method string) int {
+	t.Helper()
+
+	body := `{"jsonrpc":"2.0","id":1,"method":"` + method + `","params":[]}`
+	// The URL is replaced by the transport
+	req, err := http.NewRequest(http.MethodPost, "http://placeholder", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip() error = %v", err)
+	}
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+func TestFailoverTransport(t *testing.T) {
+	t.Run("read fails over", func(t *testing.T) {
+		first, second := newFakeNode(t, "0x1", 10), newFakeNode(t, "0x1", 10)
+		first.status.Store(http.StatusServiceUnavailable)
+		transport := newTestFailoverTransport(t, first, second)
+
+		if status := postRpc(t, transport, "starknet_getNonce"); status != http.StatusOK {
+			t.Fatalf("status = %d, want 200", status)
+		}
+		// The failed endpoint is skipped for the next request
+		if status := postRpc(t, transport, "starknet_getNonce"); status != http.StatusOK {
+			t.Fatalf("status = %d, want 200", status)
+		}
+		if first.calls.Load() != 1 || second.calls.Load() != 2 {
+			t.Errorf("endpoints got %d and %d requests, want 1 and 2", first.calls.Load(), second.calls.Load())
+		}
+	})
+
+	t.Run("transaction isn't failed over", func(t *testing.T) {
+		first, second := newFakeNode(t, "0x1", 10), newFakeNode(t, "0x1", 10)
+		first.status.Store(http.StatusServiceUnavailable)
+		transport := newTestFailoverTransport(t, first, second)
+
+		if status := postRpc(t, transport, "starknet_addInvokeTransaction"); status != http.StatusServiceUnavailable {
+			t.Fatalf("status = %d, want 503", status)
+		}
+		if second.calls.Load() != 0 {
+			t.Errorf("second endpoint got %d requests, want 0", second.calls.Load())
+		}
+	})
+
+	t.Run("lagging endpoint is used last", func(t *testing.T) {
+		lagging, synced := newFakeNode(t, "0x1", 100), newFakeNode(t, "0x1", 100+DefaultMaxBlockLag+1)
+		transport := newTestFailoverTransport(t, lagging, synced)
+
+		results := transport.CheckHealth(context.Background())
+		for _, result := range results {
+			if result.Err != nil {
+				t.Fatalf("CheckHealth() %s error = %v", result.URL, result.Err)
+			}
+		}
+		lagging.calls.Store(0)
+
+		if status := postRpc(t, transport, "starknet_getNonce"); status != http.StatusOK {
+			t.Fatalf("status = %d, want 200", status)
+		}
+		if lagging.calls.Load() != 0 {
+			t.Errorf("lagging endpoint got %d requests, want 0", lagging.calls.Load())
+		}
+	})
+}
+
+func TestCheckEndpointsAgree(t *testing.T) {
+	unreachable := errors.New("connection refused")
+
+	tests := []struct {
+		name         string
+		results      []EndpointHealth
+		wantChainId  string
+		wantFailures int
+		wantErr      bool
+	}{
+		{
+			name: "same chain",
+			results: []EndpointHealth{
+				{URL: "a", ChainId: "0x1"},
+				{URL: "b", ChainId: "0x1"},
+			},
+			wantChainId: "0x1",
+		},
+		{
+			name: "one unreachable",
+			results: []EndpointHealth{
+				{URL: "a", Err: unreachable},
+				{URL: "b", ChainId: "0x1"},
+			},
+			wantChainId:  "0x1",
+			wantFailures: 1,
+		},
+		{
+			name: "different chains",
+			results: []EndpointHealth{
+				{URL: "a", ChainId: "0x1"},
+				{URL: "b", ChainId: "0x2"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "none reachable",
+			results: []EndpointHealth{
+				{URL: "a", Err: unreachable},
+				{URL: "b", Err: unreachable},
+			},
+			wantFailures: 2,
+			wantErr:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chainId, failures, err := checkEndpointsAgree(tt.results)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("checkEndpointsAgree() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if chainId != tt.wantChainId {
+				t.Errorf("chain ID = %q, want %q", chainId, tt.wantChainId)
+			}
+			if len(failures) != tt.wantFailures {
+				t.Errorf("failures = %v, want %d", failures, tt.wantFailures)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/account"
+	"github.com/NethermindEth/starknet.go/rpc"
+)
+
+// fakeRpcProvider answers the RPC calls the tests set, other calls panic.
+type fakeRpcProvider struct {
+	rpc.RpcProvider
+
+	nonce              func(address *felt.Felt) (*felt.Felt, error)
+	transactionReceipt func(transactionHash *felt.Felt) (*rpc.TransactionReceiptWithBlockInfo, error)
+	transactionStatus  func(transactionHash *felt.Felt) (*rpc.TxnStatusResp, error)
+	simulate           func(txns []rpc.BroadcastTxn) ([]rpc.SimulatedTransaction, error)
+	class              func(classHash *felt.Felt) (rpc.ClassOutput, error)
+	addInvoke          func(tx rpc.BroadcastInvokeTxnType) (*rpc.AddInvokeTransactionResponse, error)
+}
+
+// newFakeAccount returns an account sending through client.
+func newFakeAccount(t *testing.T, client *fakeRpcProvider, address *felt.Felt) *account.Account {
+	t.Helper()
+
+	a, err := account.NewAccount(client, address, "0x1", nil, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return a
+}
+
+func (p *fakeRpcProvider) ChainID(ctx context.Context) (string, error) {
+	return "SN_SEPOLIA", nil
+}
+
+func (p *fakeRpcProvider) Nonce(ctx context.Context, blockID rpc.BlockID, address *felt.Felt) (*felt.Felt, error) {
+	return p.nonce(address)
+}
+
+func (p *fakeRpcProvider) TransactionReceipt(ctx context.Context, transactionHash *felt.Felt) (*rpc.TransactionReceiptWithBlockInfo, error) {
+	return p.transactionReceipt(transactionHash)
+}
+
+func (p *fakeRpcProvider) GetTransactionStatus(ctx context.Context, transactionHash *felt.Felt) (*rpc.TxnStatusResp, error) {
+	return p.transactionStatus(transactionHash)
+}
+
+func (p *fakeRpcProvider) SimulateTransactions(ctx context.Context, blockID rpc.BlockID, txns []rpc.BroadcastTxn, simulationFlags []rpc.SimulationFlag) ([]rpc.SimulatedTransaction, error) {
+	return p.simulate(txns)
+}
+
+func (p *fakeRpcProvider) Class(ctx context.Context, blockID rpc.BlockID, classHash *felt.Felt) (rpc.ClassOutput, error) {
+	return p.class(classHash)
+}
+
+func (p *fakeRpcProvider) AddInvokeTransaction(ctx context.Context, invokeTxn rpc.BroadcastInvokeTxnType) (*rpc.AddInvokeTransactionResponse, error) {
+	return p.addInvoke(invokeTxn)
+}
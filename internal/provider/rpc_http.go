@@ -0,0 +1,129 @@
+package provider
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+)
+
+// compressMinSize is the smallest request body compressed when compression
+// is enabled, smaller requests don't benefit from it.
+const compressMinSize = 1024
+
+// HTTPOptions configures the HTTP client used to reach the node.
+type HTTPOptions struct {
+	Headers          map[string]string
+	CABundlePath     string
+	ClientCertPath   string
+	ClientKeyPath    string
+	ProxyURL         string
+	CompressRequests bool
+}
+
+// NewHTTPTransport builds the transport sending requests to the node: TLS
+// and proxy settings are applied to the connection, headers and compression
+// to every request.
+func NewHTTPTransport(options HTTPOptions) (http.RoundTripper, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if options.CABundlePath != "" || options.ClientCertPath != "" || options.ClientKeyPath != "" {
+		tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+		if options.CABundlePath != "" {
+			pem, err := os.ReadFile(options.CABundlePath)
+			if err != nil {
+				return nil, fmt.Errorf("can't read CA bundle: %w", err)
+			}
+			pool, err := x509.SystemCertPool()
+			if err != nil {
+				pool = x509.NewCertPool()
+			}
+			if !pool.AppendCertsFromPEM(pem) {
+				return nil, fmt.Errorf("no certificates found in CA bundle %s", options.CABundlePath)
+			}
+			tlsConfig.RootCAs = pool
+		}
+
+		if options.ClientCertPath != "" || options.ClientKeyPath != "" {
+			if options.ClientCertPath == "" || options.ClientKeyPath == "" {
+				return nil, fmt.Errorf("client certificate and client key must be set together")
+			}
+			certificate, err := tls.LoadX509KeyPair(options.ClientCertPath, options.ClientKeyPath)
+			if err != nil {
+				return nil, fmt.Errorf("can't load client certificate: %w", err)
+			}
+			tlsConfig.Certificates = []tls.Certificate{certificate}
+		}
+
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	if options.ProxyURL != "" {
+		proxyURL, err := url.Parse(options.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy url %q: %w", options.ProxyURL, err)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	return &requestTransport{
+		Base:     transport,
+		Headers:  options.Headers,
+		Compress: options.CompressRequests,
+	}, nil
+}
+
+// requestTransport adds headers to requests and compresses large bodies.
+type requestTransport struct {
+	Base     http.RoundTripper
+	Headers  map[string]string
+	Compress bool
+}
+
+func (t *requestTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if len(t.Headers) == 0 && !t.Compress {
+		return t.Base.RoundTrip(req)
+	}
+
+	req = req.Clone(req.Context())
+	for name, value := range t.Headers {
+		req.Header.Set(name, value)
+	}
+
+	if t.Compress && req.Body != nil && req.Header.Get("Content-Encoding") == "" {
+		body, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		if len(body) >= compressMinSize {
+			var compressed bytes.Buffer
+			writer := gzip.NewWriter(&compressed)
+			if _, err := writer.Write(body); err != nil {
+				return nil, err
+			}
+			if err := writer.Close(); err != nil {
+				return nil, err
+			}
+			body = compressed.Bytes()
+			req.Header.Set("Content-Encoding", "gzip")
+		}
+
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		req.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(body)), nil
+		}
+		req.ContentLength = int64(len(body))
+		req.Header.Set("Content-Length", strconv.Itoa(len(body)))
+	}
+
+	return t.Base.RoundTrip(req)
+}
@@ -0,0 +1,197 @@
+package provider
+
+import (
+	"compress/gzip"
+	"encoding/pem"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// recordedRequest is what the test server got.
+type recordedRequest struct {
+	header http.Header
+	host   string
+	body   string
+}
+
+func newRecordingServer(t *testing.T, tls bool) (*httptest.Server, *recordedRequest) {
+	t.Helper()
+
+	recorded := &recordedRequest{}
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		recorded.header = r.Header.Clone()
+		recorded.host = r.Host
+
+		body := io.Reader(r.Body)
+		if r.Header.Get("Content-Encoding") == "gzip" {
+			reader, err := gzip.NewReader(r.Body)
+			if err != nil {
+				t.Errorf("gzip.NewReader() error = %v", err)
+				return
+			}
+			body = reader
+		}
+		content, err := io.ReadAll(body)
+		if err != nil {
+			t.Errorf("reading request body: %v", err)
+		}
+		recorded.body = string(content)
+	})
+
+	server := httptest.NewUnstartedServer(handler)
+	// Rejected test certificates are expected, don't log the handshake errors
+	server.Config.ErrorLog = log.New(io.Discard, "", 0)
+	if tls {
+		server.StartTLS()
+	} else {
+		server.Start()
+	}
+	t.Cleanup(server.Close)
+	return server, recorded
+}
+
+func postThrough(t *testing.T, transport http.RoundTripper, url string, body string) {
+	t.Helper()
+
+	resp, err := (&http.Client{Transport: transport}).Post(url, "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("Post() error = %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+}
+
+func TestHTTPTransport(t *testing.T) {
+	smallBody := `{"jsonrpc":"2.0","id":1,"method":"starknet_chainId","params":[]}`
+	largeBody := `{"jsonrpc":"2.0","id":1,"method":"starknet_addDeclareTransaction","params":["` +
+		strings.Repeat("a", compressMinSize) + `"]}`
+
+	tests := []struct {
+		name         string
+		options      HTTPOptions
+		body         string
+		wantHeaders  map[string]string
+		wantEncoding string
+	}{
+		{
+			name:        "headers",
+			options:     HTTPOptions{Headers: map[string]string{"X-Api-Key": "secret", "Authorization": "Bearer token"}},
+			body:        smallBody,
+			wantHeaders: map[string]string{"X-Api-Key": "secret", "Authorization": "Bearer token"},
+		},
+		{
+			name:         "small body isn't compressed",
+			options:      HTTPOptions{CompressRequests: true},
+			body:         smallBody,
+			wantEncoding: "",
+		},
+		{
+			name:         "large body is compressed",
+			options:      HTTPOptions{CompressRequests: true, Headers: map[string]string{"X-Api-Key": "secret"}},
+			body:         largeBody,
+			wantHeaders:  map[string]string{"X-Api-Key": "secret"},
+			wantEncoding: "gzip",
+		},
+		{
+			name:         "compression disabled",
+			body:         largeBody,
+			wantEncoding: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, recorded := newRecordingServer(t, false)
+
+			transport, err := NewHTTPTransport(tt.options)
+			if err != nil {
+				t.Fatalf("NewHTTPTransport() error = %v", err)
+			}
+			postThrough(t, transport, server.URL, tt.body)
+
+			for name, want := range tt.wantHeaders {
+				if got := recorded.header.Get(name); got != want {
+					t.Errorf("header %s = %q, want %q", name, got, want)
+				}
+			}
+			if got := recorded.header.Get("Content-Encoding"); got != tt.wantEncoding {
+				t.Errorf("Content-Encoding = %q, want %q", got, tt.wantEncoding)
+			}
+			if recorded.body != tt.body {
+				t.Errorf("node got body of %d bytes, want the %d bytes sent", len(recorded.body), len(tt.body))
+			}
+		})
+	}
+}
+
+func TestHTTPTransportProxy(t *testing.T) {
+	proxy, recorded := newRecordingServer(t, false)
+
+	transport, err := NewHTTPTransport(HTTPOptions{ProxyURL: proxy.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	postThrough(t, transport, "http://node.invalid:9545/rpc", "{}")
+
+	if recorded.host != "node.invalid:9545" {
+		t.Errorf("proxy got request for %q, want node.invalid:9545", recorded.host)
+	}
+}
+
+func TestHTTPTransportCABundle(t *testing.T) {
+	server, _ := newRecordingServer(t, true)
+
+	// The test server certificate isn't trusted by the system pool
+	transport, err := NewHTTPTransport(HTTPOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := (&http.Client{Transport: transport}).Get(server.URL); err == nil {
+		t.Fatal("Get() without CA bundle error = nil, want certificate error")
+	}
+
+	bundlePath := filepath.Join(t.TempDir(), "ca.pem")
+	bundle := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(bundlePath, bundle, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	transport, err = NewHTTPTransport(HTTPOptions{CABundlePath: bundlePath})
+	if err != nil {
+		t.Fatalf("NewHTTPTransport() error = %v", err)
+	}
+	postThrough(t, transport, server.URL, "{}")
+}
+
+func TestNewHTTPTransportInvalid(t *testing.T) {
+	emptyBundle := filepath.Join(t.TempDir(), "empty.pem")
+	if err := os.WriteFile(emptyBundle, []byte("no certificates"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		options HTTPOptions
+	}{
+		{name: "missing CA bundle", options: HTTPOptions{CABundlePath: filepath.Join(t.TempDir(), "missing.pem")}},
+		{name: "CA bundle without certificates", options: HTTPOptions{CABundlePath: emptyBundle}},
+		{name: "client certificate without key", options: HTTPOptions{ClientCertPath: "client.pem"}},
+		{name: "invalid proxy url", options: HTTPOptions{ProxyURL: "http://proxy:port"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewHTTPTransport(tt.options); err == nil {
+				t.Error("NewHTTPTransport() error = nil, want error")
+			}
+		})
+	}
+}
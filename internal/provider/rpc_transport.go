@@ -0,0 +1,285 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/http/cookiejar"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/NethermindEth/starknet.go/rpc"
+	ethrpc "github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	DefaultMaxRetries      = 3
+	DefaultRetryMinBackoff = 500 * time.Millisecond
+	DefaultRetryMaxBackoff = 30 * time.Second
+)
+
+// idempotentMethods are the JSON-RPC methods that are safe to resend. Methods
+// adding transactions are never retried, a resent transaction could be
+// accepted twice or fail with a nonce error hiding the original result.
+var idempotentMethods = map[string]bool{
+	"starknet_specVersion":                     true,
+	"starknet_chainId":                         true,
+	"starknet_syncing":                         true,
+	"starknet_blockNumber":                     true,
+	"starknet_blockHashAndNumber":              true,
+	"starknet_getBlockWithTxHashes":            true,
+	"starknet_getBlockWithTxs":                 true,
+	"starknet_getBlockWithReceipts":            true,
+	"starknet_getBlockTransactionCount":        true,
+	"starknet_getStateUpdate":                  true,
+	"starknet_getStorageAt":                    true,
+	"starknet_getNonce":                        true,
+	"starknet_getClass":                        true,
+	"starknet_getClassAt":                      true,
+	"starknet_getClassHashAt":                  true,
+	"starknet_getCompiledCasm":                 true,
+	"starknet_getTransactionStatus":            true,
+	"starknet_getTransactionByHash":            true,
+	"starknet_getTransactionByBlockIdAndIndex": true,
+	"starknet_getTransactionReceipt":           true,
+	"starknet_getEvents":                       true,
+	"starknet_getMessagesStatus":               true,
+	"starknet_getStorageProof":                 true,
+	"starknet_call":                            true,
+	"starknet_estimateFee":                     true,
+	"starknet_estimateMessageFee":              true,
+	"starknet_simulateTransactions":            true,
+	"starknet_traceTransaction":                true,
+	"starknet_traceBlockTransactions":          true,
+}
+
+// RetryOptions configures RetryTransport.
+type RetryOptions struct {
+	MaxRetries int
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+	// RequestsPerSecond limits the request rate, zero disables the limit.
+	RequestsPerSecond float64
+}
+
+// RetryTransport retries idempotent JSON-RPC calls failing with a network
+// error, 429 or 5xx status, and limits the request rate.
+type RetryTransport struct {
+	Base    http.RoundTripper
+	Options RetryOptions
+
+	mu   sync.Mutex
+	next time.Time
+}
+
+func NewRetryTransport(base http.RoundTripper, options RetryOptions) *RetryTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &RetryTransport{
+		Base:    base,
+		Options: options,
+	}
+}
+
+type jsonRpcRequest struct {
+	Method string `json:"method"`
+}
+
+// rpcMethods returns the methods of a single or batch JSON-RPC request.
+func rpcMethods(body []byte) []string {
+	body = bytes.TrimSpace(body)
+
+	if len(body) > 0 && body[0] == '[' {
+		var batch []jsonRpcRequest
+		if err := json.Unmarshal(body, &batch); err != nil {
+			return nil
+		}
+		methods := []string{}
+		for _, request := range batch {
+			methods = append(methods, request.Method)
+		}
+		return methods
+	}
+
+	var request jsonRpcRequest
+	if err := json.Unmarshal(body, &request); err != nil {
+		return nil
+	}
+	return []string{request.Method}
+}
+
+// isIdempotent reports whether every call of the request can be resent.
+func isIdempotent(body []byte) bool {
+	methods := rpcMethods(body)
+	if len(methods) == 0 {
+		return false
+	}
+	for _, method := range methods {
+		if !idempotentMethods[method] {
+			return false
+		}
+	}
+	return true
+}
+
+func isRetryableStatus(status int) bool {
+	return status == http.StatusTooManyRequests || status >= http.StatusInternalServerError
+}
+
+// backoff returns the delay before the given retry: exponential growth from
+// MinBackoff capped by MaxBackoff, with the upper half randomized.
+func (t *RetryTransport) backoff(retry int) time.Duration {
+	delay := t.Options.MinBackoff << retry
+	if delay <= 0 || delay > t.Options.MaxBackoff {
+		delay = t.Options.MaxBackoff
+	}
+	half := delay / 2
+	if half <= 0 {
+		return delay
+	}
+	return half + time.Duration(rand.Int63n(int64(half)))
+}
+
+// retryAfter parses the Retry-After header, either seconds or an HTTP date.
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		delay := time.Until(date)
+		if delay < 0 {
+			delay = 0
+		}
+		return delay, true
+	}
+	return 0, false
+}
+
+func sleep(ctx context.Context, delay time.Duration) error {
+	if delay <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// wait blocks until the rate limit allows the next request.
+func (t *RetryTransport) wait(ctx context.Context) error {
+	if t.Options.RequestsPerSecond <= 0 {
+		return nil
+	}
+	interval := time.Duration(float64(time.Second) / t.Options.RequestsPerSecond)
+
+	t.mu.Lock()
+	now := time.Now()
+	if t.next.Before(now) {
+		t.next = now
+	}
+	delay := t.next.Sub(now)
+	t.next = t.next.Add(interval)
+	t.mu.Unlock()
+
+	return sleep(ctx, delay)
+}
+
+func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	maxRetries := 0
+	if isIdempotent(body) {
+		maxRetries = t.Options.MaxRetries
+	}
+
+	for retry := 0; ; retry++ {
+		if err := t.wait(ctx); err != nil {
+			return nil, err
+		}
+
+		attempt := req.Clone(ctx)
+		if body != nil {
+			attempt.Body = io.NopCloser(bytes.NewReader(body))
+			attempt.GetBody = func() (io.ReadCloser, error) {
+				return io.NopCloser(bytes.NewReader(body)), nil
+			}
+		}
+
+		resp, err := t.Base.RoundTrip(attempt)
+		if retry >= maxRetries || ctx.Err() != nil {
+			return resp, err
+		}
+		if err == nil && !isRetryableStatus(resp.StatusCode) {
+			return resp, nil
+		}
+
+		delay := t.backoff(retry)
+		if resp != nil {
+			if after, ok := retryAfter(resp); ok {
+				delay = after
+			}
+			// Drain the body so the connection can be reused
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		if err := sleep(ctx, delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// NewRpcClient creates the node client sending requests through
+// RetryTransport and FailoverTransport.
+func NewRpcClient(endpoints []string, options RetryOptions, httpOptions HTTPOptions) (*rpc.Provider, *FailoverTransport, error) {
+	transport, err := NewHTTPTransport(httpOptions)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	failover, err := NewFailoverTransport(transport, endpoints)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	httpClient := &http.Client{
+		Jar:       jar,
+		Transport: NewRetryTransport(failover, options),
+	}
+
+	// The endpoint is replaced by FailoverTransport for every request
+	client, err := rpc.NewProvider(endpoints[0], ethrpc.WithHTTPClient(httpClient))
+	if err != nil {
+		return nil, nil, err
+	}
+	return client, failover, nil
+}
@@ -0,0 +1,201 @@
+package provider
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestIsIdempotent(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want bool
+	}{
+		{name: "read", body: `{"jsonrpc":"2.0","id":1,"method":"starknet_getNonce"}`, want: true},
+		{name: "transaction", body: `{"jsonrpc":"2.0","id":1,"method":"starknet_addInvokeTransaction"}`, want: false},
+		{name: "read batch", body: `[{"method":"starknet_chainId"},{"method":"starknet_blockNumber"}]`, want: true},
+		{name: "batch with transaction", body: `[{"method":"starknet_chainId"},{"method":"starknet_addDeclareTransaction"}]`, want: false},
+		{name: "empty", body: ``, want: false},
+		{name: "invalid", body: `{"method":`, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isIdempotent([]byte(tt.body)); got != tt.want {
+				t.Errorf("isIdempotent() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryTransport(t *testing.T) {
+	const (
+		readBody        = `{"jsonrpc":"2.0","id":1,"method":"starknet_getNonce","params":[]}`
+		transactionBody = `{"jsonrpc":"2.0","id":1,"method":"starknet_addInvokeTransaction","params":[]}`
+	)
+
+	tests := []struct {
+		name string
+		body string
+		// statuses answered by the node in order, the last one repeats
+		statuses   []int
+		retryAfter string
+		wantStatus int
+		wantCalls  int32
+	}{
+		{
+			name:       "read retried until success",
+			body:       readBody,
+			statuses:   []int{http.StatusServiceUnavailable, http.StatusTooManyRequests, http.StatusOK},
+			wantStatus: http.StatusOK,
+			wantCalls:  3,
+		},
+		{
+			name:       "read retries exhausted",
+			body:       readBody,
+			statuses:   []int{http.StatusBadGateway},
+			wantStatus: http.StatusBadGateway,
+			wantCalls:  3,
+		},
+		{
+			name:       "client error not retried",
+			body:       readBody,
+			statuses:   []int{http.StatusBadRequest},
+			wantStatus: http.StatusBadRequest,
+			wantCalls:  1,
+		},
+		{
+			name:       "transaction never resent",
+			body:       transactionBody,
+			statuses:   []int{http.StatusServiceUnavailable, http.StatusOK},
+			wantStatus: http.StatusServiceUnavailable,
+			wantCalls:  1,
+		},
+		{
+			name:       "retry after header",
+			body:       readBody,
+			statuses:   []int{http.StatusTooManyRequests, http.StatusOK},
+			retryAfter: "0",
+			wantStatus: http.StatusOK,
+			wantCalls:  2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				call := int(calls.Add(1)) - 1
+				status := tt.statuses[min(call, len(tt.statuses)-1)]
+				if tt.retryAfter != "" {
+					w.Header().Set("Retry-After", tt.retryAfter)
+				}
+				w.WriteHeader(status)
+			}))
+			defer server.Close()
+
+			client := &http.Client{Transport: NewRetryTransport(nil, RetryOptions{
+				MaxRetries: 2,
+				MinBackoff: time.Millisecond,
+				MaxBackoff: 5 * time.Millisecond,
+			})}
+
+			resp, err := client.Post(server.URL, "application/json", strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatalf("Post() error = %v", err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if got := calls.Load(); got != tt.wantCalls {
+				t.Errorf("node got %d requests, want %d", got, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestRetryTransportResendsBody(t *testing.T) {
+	const body = `{"jsonrpc":"2.0","id":1,"method":"starknet_chainId","params":[]}`
+
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, err := io.ReadAll(r.Body)
+		if err != nil || string(got) != body {
+			t.Errorf("request body = %q, %v, want %q", got, err, body)
+		}
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: NewRetryTransport(nil, RetryOptions{
+		MaxRetries: 1,
+		MinBackoff: time.Millisecond,
+		MaxBackoff: time.Millisecond,
+	})}
+	resp, err := client.Post(server.URL, "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK || calls.Load() != 2 {
+		t.Errorf("status = %d after %d requests, want 200 after 2", resp.StatusCode, calls.Load())
+	}
+}
+
+func TestRetryTransportRateLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	client := &http.Client{Transport: NewRetryTransport(nil, RetryOptions{RequestsPerSecond: 50})}
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+
+	// The first request is sent immediately, the next four 20ms apart
+	if elapsed := time.Since(start); elapsed < 80*time.Millisecond {
+		t.Errorf("5 requests took %s, want at least 80ms at 50 requests per second", elapsed)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		name   string
+		value  string
+		want   time.Duration
+		wantOk bool
+	}{
+		{name: "seconds", value: "3", want: 3 * time.Second, wantOk: true},
+		{name: "date in the past", value: "Wed, 21 Oct 2015 07:28:00 GMT", want: 0, wantOk: true},
+		{name: "missing", value: "", wantOk: false},
+		{name: "invalid", value: "soon", wantOk: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{Header: http.Header{}}
+			if tt.value != "" {
+				resp.Header.Set("Retry-After", tt.value)
+			}
+			got, ok := retryAfter(resp)
+			if ok != tt.wantOk || got != tt.want {
+				t.Errorf("retryAfter() = %s, %v, want %s, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
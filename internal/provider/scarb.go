@@ -0,0 +1,123 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const DefaultScarbProfile = "dev"
+
+// StarknetArtifacts describes target/<profile>/<package>.starknet_artifacts.json.
+// Scarb writes one file per package of the workspace.
+type StarknetArtifacts struct {
+	Version   int                        `json:"version"`
+	Contracts []StarknetArtifactContract `json:"contracts"`
+}
+
+type StarknetArtifactContract struct {
+	Id           string `json:"id"`
+	PackageName  string `json:"package_name"`
+	ContractName string `json:"contract_name"`
+	ModulePath   string `json:"module_path"`
+	Artifacts    struct {
+		Sierra *string `json:"sierra"`
+		Casm   *string `json:"casm"`
+	} `json:"artifacts"`
+}
+
+// ResolveScarbArtifacts returns Sierra and CASM file paths of the contract
+// built by `scarb build` with the given profile. The starknet artifacts files
+// are the source of truth, Scarb.toml isn't read: they list the contracts of
+// every package with their resolved names and the artifacts actually built.
+// contractName is the contract name or its module path, the module path
+// picks a contract when several packages have one of the same name.
+func ResolveScarbArtifacts(projectDir string, profile string, contractName string) (string, string, error) {
+	manifestPath := filepath.Join(projectDir, "Scarb.toml")
+	if _, err := os.Stat(manifestPath); err != nil {
+		return "", "", fmt.Errorf("%s is not a Scarb project: %w", projectDir, err)
+	}
+
+	targetDir := filepath.Join(projectDir, "target", profile)
+	artifactsPaths, err := filepath.Glob(filepath.Join(targetDir, "*.starknet_artifacts.json"))
+	if err != nil {
+		return "", "", err
+	}
+	if len(artifactsPaths) == 0 {
+		return "", "", fmt.Errorf(
+			"no starknet artifacts in %s, add a [[target.starknet-contract]] section to %s and run `scarb build` first",
+			targetDir,
+			manifestPath,
+		)
+	}
+
+	type match struct {
+		contract      StarknetArtifactContract
+		artifactsPath string
+	}
+	matches := []match{}
+	names := []string{}
+	for _, artifactsPath := range artifactsPaths {
+		artifacts, err := readStarknetArtifacts(artifactsPath)
+		if err != nil {
+			return "", "", err
+		}
+
+		for _, contract := range artifacts.Contracts {
+			names = append(names, contract.ModulePath)
+			if contract.ContractName == contractName || contract.ModulePath == contractName {
+				matches = append(matches, match{contract, artifactsPath})
+			}
+		}
+	}
+
+	if len(matches) == 0 {
+		return "", "", fmt.Errorf(
+			"contract %s not found in %s, available contracts: %s",
+			contractName,
+			strings.Join(artifactsPaths, ", "),
+			strings.Join(names, ", "),
+		)
+	}
+	if len(matches) > 1 {
+		modulePaths := []string{}
+		for _, match := range matches {
+			modulePaths = append(modulePaths, match.contract.ModulePath)
+		}
+		return "", "", fmt.Errorf(
+			"contract name %s is ambiguous, use one of the module paths: %s",
+			contractName,
+			strings.Join(modulePaths, ", "),
+		)
+	}
+
+	contract, artifactsPath := matches[0].contract, matches[0].artifactsPath
+	if contract.Artifacts.Sierra == nil {
+		return "", "", fmt.Errorf("contract %s has no sierra artifact in %s", contractName, artifactsPath)
+	}
+	if contract.Artifacts.Casm == nil {
+		return "", "", fmt.Errorf(
+			"contract %s has no casm artifact in %s, set `casm = true` in the [[target.starknet-contract]] section of %s and rebuild",
+			contractName,
+			artifactsPath,
+			manifestPath,
+		)
+	}
+
+	return filepath.Join(targetDir, *contract.Artifacts.Sierra), filepath.Join(targetDir, *contract.Artifacts.Casm), nil
+}
+
+func readStarknetArtifacts(artifactsPath string) (*StarknetArtifacts, error) {
+	content, err := os.ReadFile(artifactsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var artifacts StarknetArtifacts
+	if err := json.Unmarshal(content, &artifacts); err != nil {
+		return nil, fmt.Errorf("can't parse %s: %w", artifactsPath, err)
+	}
+	return &artifacts, nil
+}
@@ -0,0 +1,181 @@
+package provider
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeScarbProject writes a Scarb.toml and the given artifacts files of the
+// dev profile.
+func writeScarbProject(t *testing.T, manifest string, artifacts map[string]string) string {
+	t.Helper()
+
+	projectDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(projectDir, "Scarb.toml"), []byte(manifest), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	targetDir := filepath.Join(projectDir, "target", DefaultScarbProfile)
+	if err := os.MkdirAll(targetDir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range artifacts {
+		if err := os.WriteFile(filepath.Join(targetDir, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return projectDir
+}
+
+func TestResolveScarbArtifacts(t *testing.T) {
+	const helloArtifacts = `{
+		"version": 1,
+		"contracts": [
+			{
+				"id": "a",
+				"package_name": "hello",
+				"contract_name": "HelloStarknet",
+				"module_path": "hello::HelloStarknet",
+				"artifacts": {
+					"sierra": "hello_HelloStarknet.contract_class.json",
+					"casm": "hello_HelloStarknet.compiled_contract_class.json"
+				}
+			},
+			{
+				"id": "b",
+				"package_name": "hello",
+				"contract_name": "Counter",
+				"module_path": "hello::Counter",
+				"artifacts": {"sierra": "hello_Counter.contract_class.json", "casm": null}
+			}
+		]
+	}`
+	const tokenArtifacts = `{
+		"version": 1,
+		"contracts": [
+			{
+				"id": "c",
+				"package_name": "token",
+				"contract_name": "HelloStarknet",
+				"module_path": "token::HelloStarknet",
+				"artifacts": {
+					"sierra": "token_HelloStarknet.contract_class.json",
+					"casm": "token_HelloStarknet.compiled_contract_class.json"
+				}
+			}
+		]
+	}`
+	// The package name is inherited from the workspace, it's taken from the
+	// artifacts file instead.
+	const workspaceManifest = "[package]\nname.workspace = true\n\n[[target.starknet-contract]]\ncasm = true\n"
+
+	tests := []struct {
+		name         string
+		manifest     string
+		artifacts    map[string]string
+		contractName string
+		wantSierra   string
+		wantCasm     string
+		wantErrIn    string
+	}{
+		{
+			name:         "contract name",
+			manifest:     workspaceManifest,
+			artifacts:    map[string]string{"hello.starknet_artifacts.json": helloArtifacts},
+			contractName: "HelloStarknet",
+			wantSierra:   "hello_HelloStarknet.contract_class.json",
+			wantCasm:     "hello_HelloStarknet.compiled_contract_class.json",
+		},
+		{
+			name:     "module path picks the package",
+			manifest: workspaceManifest,
+			artifacts: map[string]string{
+				"hello.starknet_artifacts.json": helloArtifacts,
+				"token.starknet_artifacts.json": tokenArtifacts,
+			},
+			contractName: "token::HelloStarknet",
+			wantSierra:   "token_HelloStarknet.contract_class.json",
+			wantCasm:     "token_HelloStarknet.compiled_contract_class.json",
+		},
+		{
+			name:     "ambiguous contract name",
+			manifest: workspaceManifest,
+			artifacts: map[string]string{
+				"hello.starknet_artifacts.json": helloArtifacts,
+				"token.starknet_artifacts.json": tokenArtifacts,
+			},
+			contractName: "HelloStarknet",
+			wantErrIn:    "ambiguous, use one of the module paths: hello::HelloStarknet, token::HelloStarknet",
+		},
+		{
+			// casm isn't enabled in the manifest, the artifacts decide
+			name:         "casm built without manifest flag",
+			manifest:     "[package]\nname = \"hello\"\n",
+			artifacts:    map[string]string{"hello.starknet_artifacts.json": helloArtifacts},
+			contractName: "HelloStarknet",
+			wantSierra:   "hello_HelloStarknet.contract_class.json",
+			wantCasm:     "hello_HelloStarknet.compiled_contract_class.json",
+		},
+		{
+			name:         "casm not built",
+			manifest:     workspaceManifest,
+			artifacts:    map[string]string{"hello.starknet_artifacts.json": helloArtifacts},
+			contractName: "Counter",
+			wantErrIn:    "has no casm artifact",
+		},
+		{
+			name:         "unknown contract",
+			manifest:     workspaceManifest,
+			artifacts:    map[string]string{"hello.starknet_artifacts.json": helloArtifacts},
+			contractName: "Token",
+			wantErrIn:    "available contracts: hello::HelloStarknet, hello::Counter",
+		},
+		{
+			name:         "not built",
+			manifest:     workspaceManifest,
+			contractName: "HelloStarknet",
+			wantErrIn:    "run `scarb build` first",
+		},
+		{
+			name:         "invalid artifacts",
+			manifest:     workspaceManifest,
+			artifacts:    map[string]string{"hello.starknet_artifacts.json": "{"},
+			contractName: "HelloStarknet",
+			wantErrIn:    "can't parse",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			projectDir := writeScarbProject(t, tt.manifest, tt.artifacts)
+
+			sierra, casm, err := ResolveScarbArtifacts(projectDir, DefaultScarbProfile, tt.contractName)
+			if tt.wantErrIn != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErrIn) {
+					t.Fatalf("ResolveScarbArtifacts() error = %v, want error containing %q", err, tt.wantErrIn)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ResolveScarbArtifacts() error = %v", err)
+			}
+
+			targetDir := filepath.Join(projectDir, "target", DefaultScarbProfile)
+			if want := filepath.Join(targetDir, tt.wantSierra); sierra != want {
+				t.Errorf("sierra = %s, want %s", sierra, want)
+			}
+			if want := filepath.Join(targetDir, tt.wantCasm); casm != want {
+				t.Errorf("casm = %s, want %s", casm, want)
+			}
+		})
+	}
+}
+
+func TestResolveScarbArtifactsNotAProject(t *testing.T) {
+	_, _, err := ResolveScarbArtifacts(t.TempDir(), DefaultScarbProfile, "HelloStarknet")
+	if err == nil || !strings.Contains(err.Error(), "is not a Scarb project") {
+		t.Fatalf("ResolveScarbArtifacts() error = %v, want not a Scarb project", err)
+	}
+}
@@ -0,0 +1,343 @@
+package provider
+
+import (
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/account"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// AccountModel describes the signing account settings. The provider level
+// attributes configure the default account, `account` blocks add named ones.
+type AccountModel struct {
+	Address          types.String `tfsdk:"address"`
+	PrivateKey       types.String `tfsdk:"private_key"`
+	PrivateKeyPath   types.String `tfsdk:"private_key_path"`
+	PublicKeyPath    types.String `tfsdk:"public_key_path"`
+	KeystorePath     types.String `tfsdk:"keystore_path"`
+	KeystorePassword types.String `tfsdk:"keystore_password"`
+}
+
+// NamedAccountModel describes the provider `account` block.
+type NamedAccountModel struct {
+	Name types.String `tfsdk:"name"`
+
+	AccountModel
+}
+
+// namedAccountAttributes returns the schema of the provider `account` block.
+func namedAccountAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"name": schema.StringAttribute{
+			MarkdownDescription: "Account name, referenced by the `account` argument of transaction resources.",
+			Required:            true,
+		},
+		"address": schema.StringAttribute{
+			MarkdownDescription: "Account address.",
+			Required:            true,
+		},
+		"private_key": schema.StringAttribute{
+			MarkdownDescription: "Account secret key. Conflicts with `private_key_path` and `keystore_path`. " +
+				"Can be set with the `STARKNET_ACCOUNT_<NAME>_PRIVATE_KEY` environment variable.",
+			Optional:  true,
+			Sensitive: true,
+		},
+		"private_key_path": schema.StringAttribute{
+			MarkdownDescription: "Account secret key file path. Conflicts with `private_key` and `keystore_path`. " +
+				"Can be set with the `STARKNET_ACCOUNT_<NAME>_PRIVATE_KEY_PATH` environment variable.",
+			Optional: true,
+		},
+		"keystore_path": schema.StringAttribute{
+			MarkdownDescription: "Account encrypted keystore file path. Conflicts with `private_key` and `private_key_path`. " +
+				"Can be set with the `STARKNET_ACCOUNT_<NAME>_KEYSTORE` environment variable.",
+			Optional: true,
+		},
+		"keystore_password": schema.StringAttribute{
+			MarkdownDescription: "Keystore password. " +
+				"Can be set with the `STARKNET_ACCOUNT_<NAME>_KEYSTORE_PASSWORD` environment variable.",
+			Optional:  true,
+			Sensitive: true,
+		},
+		"public_key_path": schema.StringAttribute{
+			MarkdownDescription: "Account public key file path, checked to match the key derived from the private key. " +
+				"Can be set with the `STARKNET_ACCOUNT_<NAME>_PUBLIC_KEY_PATH` environment variable.",
+			Optional: true,
+		},
+	}
+}
+
+// Signer is an account able to sign transactions.
+type Signer struct {
+	Name      string
+	Address   *felt.Felt
+	PublicKey string
+	KeyStore  *account.MemKeystore
+}
+
+// NewAccount creates a starknet.go account signing with the signer keys.
+func (s *Signer) NewAccount(client rpc.RpcProvider) (*account.Account, error) {
+	return account.NewAccount(client, s.Address, s.PublicKey, s.KeyStore, 1)
+}
+
+// Signers holds the default account and the named accounts configured on
+// the provider.
+type Signers struct {
+	// Default is nil in read-only mode.
+	Default *Signer
+	Named   map[string]*Signer
+}
+
+// Get returns the named signer, or the default one when name is empty.
+func (s Signers) Get(name string) (*Signer, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	if name == "" {
+		if s.Default == nil {
+			addNoSignerError(&diags)
+			return nil, diags
+		}
+		return s.Default, diags
+	}
+
+	signer, ok := s.Named[name]
+	if !ok {
+		names := []string{}
+		for name := range s.Named {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		diags.AddError(
+			"Unknown account",
+			fmt.Sprintf("Account %q is not configured in the provider, configured accounts: %s", name, strings.Join(names, ", ")),
+		)
+		return nil, diags
+	}
+	return signer, diags
+}
+
+// addNoSignerError reports that a transaction can't be sent because the
+// provider is configured in read-only mode.
+func addNoSignerError(diags *diag.Diagnostics) {
+	diags.AddError(
+		"No signer configured",
+		"This resource sends transactions and needs an account to sign them. "+
+			"Configure address and one of private_key, private_key_path or keystore_path in the provider, "+
+			"or the matching STARKNET_* environment variables.",
+	)
+}
+
+// Signer loads the account keys. It returns nil when no private key source
+// is configured.
+func (m AccountModel) Signer(name string) (*Signer, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	privateKey, err := m.loadPrivateKey(name)
+	if err != nil {
+		diags.AddError(
+			"Failed to load private key",
+			fmt.Sprintf("Account %s: %s", accountDisplayName(name), err),
+		)
+		return nil, diags
+	}
+
+	if privateKey == nil {
+		return nil, diags
+	}
+
+	if m.Address.IsNull() {
+		diags.AddError(
+			"Missing account address",
+			fmt.Sprintf(
+				"Account %s has a private key configured without an account address. "+
+					"Set address in the provider configuration or the "+AccountAddressEnvVar+" environment variable.",
+				accountDisplayName(name),
+			),
+		)
+		return nil, diags
+	}
+
+	address, err := utils.HexToFelt(m.Address.ValueString())
+	if err != nil {
+		diags.AddError(
+			"Invalid address",
+			fmt.Sprintf("Account %s: %s", accountDisplayName(name), err),
+		)
+		return nil, diags
+	}
+
+	publicKey, diags := m.derivePublicKey(privateKey)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	ks := account.NewMemKeystore()
+	ks.Put(publicKey.String(), privateKey)
+
+	return &Signer{
+		Name:      name,
+		Address:   address,
+		PublicKey: publicKey.String(),
+		KeyStore:  ks,
+	}, diags
+}
+
+// accountEnvVars lists account attributes with their environment variables.
+// The default account uses the STARKNET_* variables, named accounts
+// STARKNET_ACCOUNT_<NAME>_* ones. The address of named accounts is required
+// in the configuration.
+func (m *AccountModel) accountEnvVars(name string) []envFallback {
+	fallbacks := []envFallback{}
+	if name == "" {
+		fallbacks = append(fallbacks, stringEnv("address", &m.Address, AccountAddressEnvVar))
+	}
+	return append(
+		fallbacks,
+		stringEnv("private_key", &m.PrivateKey, accountEnvVar(name, PrivateKeyEnvVar)),
+		stringEnv("private_key_path", &m.PrivateKeyPath, accountEnvVar(name, PrivateKeyPathEnvVar)),
+		stringEnv("keystore_path", &m.KeystorePath, accountEnvVar(name, KeystoreEnvVar)),
+		stringEnv("keystore_password", &m.KeystorePassword, accountEnvVar(name, KeystorePasswordEnvVar)),
+		stringEnv("public_key_path", &m.PublicKeyPath, accountEnvVar(name, PublicKeyPathEnvVar)),
+	)
+}
+
+// accountEnvVar returns the environment variable of an account attribute,
+// e.g. STARKNET_ACCOUNT_DEPLOYER_PRIVATE_KEY for the STARKNET_PRIVATE_KEY
+// attribute of the "deployer" account. Characters other than letters and
+// digits in the name are replaced by underscores.
+func accountEnvVar(name string, envVar string) string {
+	if name == "" {
+		return envVar
+	}
+	normalized := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, name)
+	return "STARKNET_ACCOUNT_" + normalized + strings.TrimPrefix(envVar, "STARKNET")
+}
+
+func accountDisplayName(name string) string {
+	if name == "" {
+		return "default"
+	}
+	return fmt.Sprintf("%q", name)
+}
+
+func (m AccountModel) hasPrivateKey() bool {
+	return !m.PrivateKey.IsNull() || !m.PrivateKeyPath.IsNull() || !m.KeystorePath.IsNull()
+}
+
+// loadPrivateKey reads the private key from the configured source: the key
+// itself, the plaintext key file or the encrypted keystore. It returns nil
+// when no source is configured. name selects the environment variables
+// mentioned in errors.
+func (m AccountModel) loadPrivateKey(name string) (*big.Int, error) {
+	sources := []string{}
+	if !m.PrivateKey.IsNull() {
+		sources = append(sources, "private_key")
+	}
+	if !m.PrivateKeyPath.IsNull() {
+		sources = append(sources, "private_key_path")
+	}
+	if !m.KeystorePath.IsNull() {
+		sources = append(sources, "keystore_path")
+	}
+
+	if len(sources) == 0 {
+		return nil, nil
+	}
+	if len(sources) > 1 {
+		return nil, fmt.Errorf("only one private key source can be set, got %s", strings.Join(sources, ", "))
+	}
+
+	var secretKey string
+	switch sources[0] {
+	case "keystore_path":
+		if m.KeystorePassword.IsNull() {
+			return nil, fmt.Errorf("keystore password is not set, use keystore_password or %s", accountEnvVar(name, KeystorePasswordEnvVar))
+		}
+		return ReadEncryptedKeystore(m.KeystorePath.ValueString(), m.KeystorePassword.ValueString())
+	case "private_key_path":
+		secretKeyData, err := os.ReadFile(m.PrivateKeyPath.ValueString())
+		if err != nil {
+			return nil, err
+		}
+		secretKey = string(secretKeyData)
+	default:
+		secretKey = m.PrivateKey.ValueString()
+	}
+
+	privateKeyInt, ok := new(big.Int).SetString(strings.TrimSpace(secretKey), 0)
+	if !ok {
+		return nil, fmt.Errorf("failed to convert secret key string to big.Int")
+	}
+	return privateKeyInt, nil
+}
+
+// derivePublicKey derives the public key from the private key and checks it
+// matches the one from public_key_path when it's set.
+func (m AccountModel) derivePublicKey(privateKey *big.Int) (*felt.Felt, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	derivedPublicKey, err := PublicKeyFromPrivateKey(privateKey)
+	if err != nil {
+		diags.AddError(
+			"Invalid private key",
+			err.Error(),
+		)
+		return nil, diags
+	}
+
+	if m.PublicKeyPath.IsNull() {
+		return derivedPublicKey, diags
+	}
+
+	publicKeyData, err := os.ReadFile(m.PublicKeyPath.ValueString())
+	if err != nil {
+		diags.AddError(
+			"Failed to read public key file",
+			err.Error(),
+		)
+		return nil, diags
+	}
+
+	configuredPublicKey, err := utils.HexToFelt(strings.TrimSpace(string(publicKeyData)))
+	if err != nil {
+		diags.AddError(
+			"Invalid public key",
+			err.Error(),
+		)
+		return nil, diags
+	}
+
+	if !configuredPublicKey.Equal(derivedPublicKey) {
+		diags.AddError(
+			"Public key mismatch",
+			fmt.Sprintf(
+				"Public key %s from %s doesn't match public key %s derived from the private key",
+				configuredPublicKey,
+				m.PublicKeyPath.ValueString(),
+				derivedPublicKey,
+			),
+		)
+		return nil, diags
+	}
+
+	return derivedPublicKey, diags
+}
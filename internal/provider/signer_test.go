@@ -0,0 +1,107 @@
+package provider
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Keypairs of the starknet.go account tests and of the first
+// starknet-devnet account with seed 0.
+var testKeypairs = []struct {
+	privateKey string
+	publicKey  string
+}{
+	{
+		privateKey: "0x04818374f8071c3b4c3070ff7ce766e7b9352628df7b815ea4de26e0fadb5cc9",
+		publicKey:  "0x22288424ec8116c73d2e2ed3b0663c5030d328d9c0fb44c2b54055db467f31e",
+	},
+	{
+		privateKey: "0x71d7bb07b9a64f6f78ac4c816aff4da9",
+		publicKey:  "0x39d9e6ce352ad4530a0ef5d5a18fd3303c3606a7fa6ac5b620020ad681cc33b",
+	},
+}
+
+func TestPublicKeyFromPrivateKey(t *testing.T) {
+	for _, keypair := range testKeypairs {
+		t.Run(keypair.privateKey, func(t *testing.T) {
+			privateKey, _ := new(big.Int).SetString(keypair.privateKey, 0)
+
+			publicKey, err := PublicKeyFromPrivateKey(privateKey)
+			if err != nil {
+				t.Fatalf("PublicKeyFromPrivateKey() error = %v", err)
+			}
+			if got := publicKey.String(); got != keypair.publicKey {
+				t.Errorf("PublicKeyFromPrivateKey() = %s, want %s", got, keypair.publicKey)
+			}
+		})
+	}
+}
+
+func TestAccountModelDerivePublicKey(t *testing.T) {
+	keypair, other := testKeypairs[0], testKeypairs[1]
+	privateKey, _ := new(big.Int).SetString(keypair.privateKey, 0)
+
+	tests := []struct {
+		name string
+		// publicKeyFile is written to public_key_path unless empty
+		publicKeyFile string
+		wantErr       string
+	}{
+		{name: "no public key path"},
+		{name: "matching public key", publicKeyFile: keypair.publicKey + "\n"},
+		{name: "other account public key", publicKeyFile: other.publicKey, wantErr: "Public key mismatch"},
+		{name: "invalid public key", publicKeyFile: "not a key", wantErr: "Invalid public key"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model := AccountModel{PublicKeyPath: types.StringNull()}
+			if tt.publicKeyFile != "" {
+				publicKeyPath := filepath.Join(t.TempDir(), "public_key")
+				if err := os.WriteFile(publicKeyPath, []byte(tt.publicKeyFile), 0o600); err != nil {
+					t.Fatal(err)
+				}
+				model.PublicKeyPath = types.StringValue(publicKeyPath)
+			}
+
+			publicKey, diags := model.derivePublicKey(privateKey)
+			if tt.wantErr != "" {
+				if !diags.HasError() || diags[0].Summary() != tt.wantErr {
+					t.Fatalf("derivePublicKey() = %v, want %q error", diags, tt.wantErr)
+				}
+				return
+			}
+			if diags.HasError() {
+				t.Fatalf("derivePublicKey() = %v", diags)
+			}
+			if got := publicKey.String(); got != keypair.publicKey {
+				t.Errorf("derivePublicKey() = %s, want %s", got, keypair.publicKey)
+			}
+		})
+	}
+}
+
+func TestAccountModelSigner(t *testing.T) {
+	keypair := testKeypairs[1]
+	model := AccountModel{
+		Address:    types.StringValue("0x64b48806902a367c8598f4f95c305e8c1a1acba5f082d294a43793113115691"),
+		PrivateKey: types.StringValue(keypair.privateKey),
+	}
+
+	signer, diags := model.Signer("deployer")
+	if diags.HasError() {
+		t.Fatalf("Signer() = %v", diags)
+	}
+	if signer.PublicKey != keypair.publicKey {
+		t.Errorf("Signer() public key = %s, want %s", signer.PublicKey, keypair.publicKey)
+	}
+
+	model.KeystorePath = types.StringValue("keystore.json")
+	if _, diags := model.Signer("deployer"); !diags.HasError() {
+		t.Error("Signer() with private_key and keystore_path = no error, want conflicting sources error")
+	}
+}
@@ -0,0 +1,114 @@
+package provider
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SpecVersion is the Starknet JSON-RPC specification version served by the
+// node, e.g. 0.7.1.
+type SpecVersion struct {
+	Major int
+	Minor int
+	Patch int
+}
+
+// supportedSpecVersions lists the spec versions the provider works with, by
+// major.minor. Only 0.7 is supported: the pinned starknet.go client only has
+// 0.7 transaction, receipt and fee estimate types, and 0.8 changed all three,
+// V3 transactions carry a separate l1_data_gas bound that's part of the
+// signed hash and estimates report L2 gas. 0.8 and 0.9 need builders of
+// their own, which come with a client upgrade. Until then other versions
+// are refused instead of being sent 0.7 payloads the node would misread.
+var supportedSpecVersions = map[string]bool{
+	"0.7": true,
+}
+
+func ParseSpecVersion(value string) (SpecVersion, error) {
+	// Pre-release suffixes like 0.8.0-rc.1 are ignored
+	version, _, _ := strings.Cut(strings.TrimPrefix(strings.TrimSpace(value), "v"), "-")
+	parts := strings.Split(version, ".")
+	if len(parts) < 2 || len(parts) > 3 {
+		return SpecVersion{}, fmt.Errorf("invalid spec version %q", value)
+	}
+
+	numbers := []int{0, 0, 0}
+	for i, part := range parts {
+		number, err := strconv.Atoi(part)
+		if err != nil {
+			return SpecVersion{}, fmt.Errorf("invalid spec version %q", value)
+		}
+		numbers[i] = number
+	}
+
+	return SpecVersion{Major: numbers[0], Minor: numbers[1], Patch: numbers[2]}, nil
+}
+
+func (v SpecVersion) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// MajorMinor returns the version without the patch part, which doesn't
+// change the API.
+func (v SpecVersion) MajorMinor() string {
+	return fmt.Sprintf("%d.%d", v.Major, v.Minor)
+}
+
+func supportedSpecVersionNames() string {
+	names := []string{}
+	for name := range supportedSpecVersions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// CheckSupported returns an error when the provider can't work with nodes
+// serving this spec version.
+func (v SpecVersion) CheckSupported() error {
+	if supportedSpecVersions[v.MajorMinor()] {
+		return nil
+	}
+	return fmt.Errorf(
+		"JSON-RPC spec version %s is not supported, supported versions: %s. "+
+			"Juno and Pathfinder serve older spec versions on versioned paths, e.g. /rpc/v0_7",
+		v,
+		supportedSpecVersionNames(),
+	)
+}
+
+// negotiateSpecVersion checks the reachable endpoints serve the same
+// supported spec version and returns it.
+func negotiateSpecVersion(results []EndpointHealth) (SpecVersion, error) {
+	var negotiated SpecVersion
+	negotiatedURL := ""
+
+	for _, result := range results {
+		if result.Err != nil {
+			continue
+		}
+
+		version, err := ParseSpecVersion(result.SpecVersion)
+		if err != nil {
+			return SpecVersion{}, fmt.Errorf("%s: %w", result.URL, err)
+		}
+		if err := version.CheckSupported(); err != nil {
+			return SpecVersion{}, fmt.Errorf("%s: %w", result.URL, err)
+		}
+
+		if negotiatedURL == "" {
+			negotiated, negotiatedURL = version, result.URL
+			continue
+		}
+		if version.MajorMinor() != negotiated.MajorMinor() {
+			return SpecVersion{}, fmt.Errorf(
+				"rpc endpoints serve different spec versions: %s serves %s, %s serves %s",
+				negotiatedURL, negotiated, result.URL, version,
+			)
+		}
+	}
+
+	return negotiated, nil
+}
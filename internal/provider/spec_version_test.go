@@ -0,0 +1,133 @@
+package provider
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseSpecVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    SpecVersion
+		wantErr bool
+	}{
+		{name: "major minor patch", value: "0.7.1", want: SpecVersion{0, 7, 1}},
+		{name: "major minor", value: "0.7", want: SpecVersion{0, 7, 0}},
+		{name: "v prefix", value: "v0.8.0", want: SpecVersion{0, 8, 0}},
+		{name: "pre-release", value: "0.8.0-rc.1", want: SpecVersion{0, 8, 0}},
+		{name: "whitespace", value: " 0.7.0\n", want: SpecVersion{0, 7, 0}},
+		{name: "single part", value: "7", wantErr: true},
+		{name: "too many parts", value: "0.7.1.2", wantErr: true},
+		{name: "not a number", value: "0.x.1", wantErr: true},
+		{name: "empty", value: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSpecVersion(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSpecVersion(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseSpecVersion(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSpecVersionCheckSupported(t *testing.T) {
+	tests := []struct {
+		version SpecVersion
+		wantErr bool
+	}{
+		{version: SpecVersion{0, 7, 0}},
+		{version: SpecVersion{0, 7, 1}},
+		{version: SpecVersion{0, 6, 0}, wantErr: true},
+		{version: SpecVersion{0, 8, 0}, wantErr: true},
+		{version: SpecVersion{0, 9, 0}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version.String(), func(t *testing.T) {
+			err := tt.version.CheckSupported()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CheckSupported() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNegotiateSpecVersion(t *testing.T) {
+	tests := []struct {
+		name      string
+		results   []EndpointHealth
+		want      SpecVersion
+		wantErrIn string
+	}{
+		{
+			name: "single endpoint",
+			results: []EndpointHealth{
+				{URL: "http://a", SpecVersion: "0.7.1"},
+			},
+			want: SpecVersion{0, 7, 1},
+		},
+		{
+			name: "patch versions differ",
+			results: []EndpointHealth{
+				{URL: "http://a", SpecVersion: "0.7.0"},
+				{URL: "http://b", SpecVersion: "0.7.1"},
+			},
+			want: SpecVersion{0, 7, 0},
+		},
+		{
+			name: "unreachable endpoints are skipped",
+			results: []EndpointHealth{
+				{URL: "http://a", Err: errors.New("connection refused")},
+				{URL: "http://b", SpecVersion: "0.7.1"},
+			},
+			want: SpecVersion{0, 7, 1},
+		},
+		{
+			name: "unsupported version",
+			results: []EndpointHealth{
+				{URL: "http://a", SpecVersion: "0.8.0"},
+			},
+			wantErrIn: "http://a: JSON-RPC spec version 0.8.0 is not supported",
+		},
+		{
+			name: "invalid version",
+			results: []EndpointHealth{
+				{URL: "http://a", SpecVersion: "latest"},
+			},
+			wantErrIn: "invalid spec version",
+		},
+		{
+			name: "endpoints disagree",
+			results: []EndpointHealth{
+				{URL: "http://a", SpecVersion: "0.7.1"},
+				{URL: "http://b", SpecVersion: "0.6.0"},
+			},
+			wantErrIn: "http://b: JSON-RPC spec version 0.6.0 is not supported",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := negotiateSpecVersion(tt.results)
+			if tt.wantErrIn != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErrIn) {
+					t.Fatalf("negotiateSpecVersion() error = %v, want error containing %q", err, tt.wantErrIn)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("negotiateSpecVersion() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("negotiateSpecVersion() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
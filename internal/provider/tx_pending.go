@@ -0,0 +1,242 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+// pendingTransactionKey is the private state key of PendingTransaction.
+const pendingTransactionKey = "pending_transaction"
+
+const (
+	// pendingCheckTimeout bounds the status check of a pending transaction
+	// on refresh, refresh doesn't wait for the transaction.
+	pendingCheckTimeout = 30 * time.Second
+	// pendingDroppedAfter is how long after sending a transaction the node
+	// may not know it before it's considered dropped. It covers a node
+	// that hasn't seen the transaction yet, e.g. a lagging failover
+	// endpoint.
+	pendingDroppedAfter = 10 * time.Minute
+)
+
+// PendingStatus is the outcome of checking a pending transaction.
+type PendingStatus int
+
+const (
+	// PendingNone means no transaction is pending.
+	PendingNone PendingStatus = iota
+	// PendingLanded means the transaction reached the awaited status, or
+	// reverted, and has a receipt.
+	PendingLanded
+	// PendingWaiting means the transaction hasn't reached the awaited
+	// status yet.
+	PendingWaiting
+	// PendingDropped means the node still doesn't know the transaction
+	// pendingDroppedAfter after it was sent.
+	PendingDropped
+)
+
+// PrivateState is implemented by the resource private state of the
+// framework requests and responses.
+type PrivateState interface {
+	GetKey(ctx context.Context, key string) ([]byte, diag.Diagnostics)
+	SetKey(ctx context.Context, key string, value []byte) diag.Diagnostics
+}
+
+// PendingTransaction is kept in the resource private state from the moment
+// the transaction is sent until its receipt is in the state. When an apply
+// stops before that, the next refresh checks this transaction instead of
+// the next apply sending a new one.
+type PendingTransaction struct {
+	TransactionHash string  `json:"transaction_hash"`
+	Nonce           string  `json:"nonce"`
+	Account         string  `json:"account,omitempty"`
+	SenderAddress   string  `json:"sender_address"`
+	WaitFor         WaitFor `json:"wait_for"`
+	PollInterval    string  `json:"poll_interval"`
+	// SentAt is zero for transactions saved before it was recorded, those
+	// are considered dropped as soon as the node doesn't know them.
+	SentAt time.Time `json:"sent_at"`
+}
+
+func NewPendingTransaction(submitted *SubmittedTransaction, signer *Signer, options *WaitOptions) *PendingTransaction {
+	return &PendingTransaction{
+		TransactionHash: submitted.TransactionHash.String(),
+		Nonce:           submitted.Nonce.String(),
+		Account:         signer.Name,
+		SenderAddress:   signer.Address.String(),
+		WaitFor:         options.WaitFor,
+		PollInterval:    options.PollInterval.String(),
+		SentAt:          time.Now().UTC(),
+	}
+}
+
+// WaitOptions returns the options the transaction was sent with. Resumed
+// transactions are waited for at least until they have a receipt.
+func (p *PendingTransaction) WaitOptions() *WaitOptions {
+	options := &WaitOptions{
+		WaitFor:      p.WaitFor,
+		PollInterval: DefaultPollInterval,
+	}
+	if options.WaitFor == "" || options.WaitFor == WaitForNone {
+		options.WaitFor = WaitForPreConfirmed
+	}
+	if interval, err := time.ParseDuration(p.PollInterval); err == nil && interval > 0 {
+		options.PollInterval = interval
+	}
+	return options
+}
+
+func (p *PendingTransaction) transactionHash() (*felt.Felt, error) {
+	transactionHash, err := utils.HexToFelt(p.TransactionHash)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pending transaction hash %q: %w", p.TransactionHash, err)
+	}
+	return transactionHash, nil
+}
+
+// Check polls the pending transaction receipt once. A reverted transaction
+// is returned as a receipt, the caller checks it with RevertError.
+func (p *PendingTransaction) Check(ctx context.Context, client rpc.RpcProvider) (*rpc.TransactionReceiptWithBlockInfo, PendingStatus, error) {
+	transactionHash, err := p.transactionHash()
+	if err != nil {
+		return nil, PendingWaiting, err
+	}
+
+	receipt, err := client.TransactionReceipt(ctx, transactionHash)
+	if err != nil {
+		if rpcErr, ok := err.(*rpc.RPCError); ok && rpcErr.Code == rpc.ErrHashNotFound.Code {
+			if time.Since(p.SentAt) >= pendingDroppedAfter {
+				return nil, PendingDropped, nil
+			}
+			return nil, PendingWaiting, nil
+		}
+		return nil, PendingWaiting, err
+	}
+
+	if receipt.ExecutionStatus == rpc.TxnExecutionStatusREVERTED || receiptReached(receipt, p.WaitOptions().WaitFor) {
+		return receipt, PendingLanded, nil
+	}
+	return nil, PendingWaiting, nil
+}
+
+// addPendingWarning reports a transaction that is still pending on refresh.
+// The resource is kept, so the transaction is checked again on the next
+// refresh instead of being sent again.
+func addPendingWarning(diags *diag.Diagnostics, transactionHash string, err error) {
+	detail := fmt.Sprintf("Transaction %s hasn't landed yet, it will be checked again on the next refresh.", transactionHash)
+	if err != nil {
+		detail += fmt.Sprintf(" Checking it failed: %s", err)
+	}
+	diags.AddWarning("Transaction is still pending", detail)
+}
+
+// addStoppedWaiting reports a sent transaction the provider stopped waiting
+// for on create, it's kept in private state as it may still land. When the
+// apply was interrupted or timed out the resource is saved with a warning,
+// so it isn't tainted and the next refresh checks the transaction instead
+// of the next apply sending a new one. Other errors taint the resource.
+func addStoppedWaiting(ctx context.Context, diags *diag.Diagnostics, transactionHash string, err error) {
+	if ctx.Err() != nil {
+		diags.AddWarning(
+			"Transaction is still pending",
+			fmt.Sprintf(
+				"Stopped waiting for transaction %s: %s\n\n"+
+					"The transaction may still land, it will be checked on the next refresh instead of sending a new one.",
+				transactionHash, err,
+			),
+		)
+		return
+	}
+
+	diags.AddError(
+		"Transaction is still pending",
+		fmt.Sprintf(
+			"Stopped waiting for transaction %s: %s\n\n"+
+				"The transaction may still land. The resource is tainted, so the next apply replaces it and sends "+
+				"a new transaction. Run `terraform untaint` to keep the transaction and check it on the next "+
+				"refresh instead.",
+			transactionHash, err,
+		),
+	)
+}
+
+func savePendingTransaction(ctx context.Context, private PrivateState, pending *PendingTransaction) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	value, err := json.Marshal(pending)
+	if err != nil {
+		diags.AddError("Can't save pending transaction", err.Error())
+		return diags
+	}
+	return private.SetKey(ctx, pendingTransactionKey, value)
+}
+
+// readPendingTransaction returns nil when no transaction is pending.
+func readPendingTransaction(ctx context.Context, private PrivateState) (*PendingTransaction, diag.Diagnostics) {
+	value, diags := private.GetKey(ctx, pendingTransactionKey)
+	if diags.HasError() || len(value) == 0 {
+		return nil, diags
+	}
+
+	var pending PendingTransaction
+	if err := json.Unmarshal(value, &pending); err != nil {
+		diags.AddError("Can't read pending transaction", err.Error())
+		return nil, diags
+	}
+	return &pending, diags
+}
+
+func clearPendingTransaction(ctx context.Context, private PrivateState) diag.Diagnostics {
+	return private.SetKey(ctx, pendingTransactionKey, nil)
+}
+
+// checkPendingTransaction checks once on the transaction an interrupted
+// apply left in private state. The private state is cleared when the
+// transaction landed or was dropped, a dropped transaction also resyncs the
+// nonce of its account.
+func checkPendingTransaction(
+	ctx context.Context,
+	client rpc.RpcProvider,
+	nonces *NonceManager,
+	private PrivateState,
+) (*rpc.TransactionReceiptWithBlockInfo, PendingStatus, diag.Diagnostics) {
+	pending, diags := readPendingTransaction(ctx, private)
+	if diags.HasError() || pending == nil {
+		return nil, PendingNone, diags
+	}
+
+	checkCtx, cancel := context.WithTimeout(ctx, pendingCheckTimeout)
+	defer cancel()
+
+	receipt, status, err := pending.Check(checkCtx, client)
+	switch status {
+	case PendingWaiting:
+		addPendingWarning(&diags, pending.TransactionHash, err)
+	case PendingDropped:
+		diags.AddWarning(
+			"Transaction was dropped",
+			fmt.Sprintf(
+				"The node doesn't know transaction %s sent at %s, the resource will be created again.",
+				pending.TransactionHash, pending.SentAt.Format(time.RFC3339),
+			),
+		)
+		diags.Append(clearPendingTransaction(ctx, private)...)
+		if sender, err := utils.HexToFelt(pending.SenderAddress); err == nil && nonces != nil {
+			if err := nonces.Resync(ctx, sender); err != nil {
+				diags.AddWarning("Can't resync account nonce", err.Error())
+			}
+		}
+	case PendingLanded:
+		diags.Append(clearPendingTransaction(ctx, private)...)
+	}
+	return receipt, status, diags
+}
@@ -0,0 +1,199 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+// fakePrivateState keeps private state keys in memory.
+type fakePrivateState map[string][]byte
+
+func (s fakePrivateState) GetKey(ctx context.Context, key string) ([]byte, diag.Diagnostics) {
+	return s[key], nil
+}
+
+func (s fakePrivateState) SetKey(ctx context.Context, key string, value []byte) diag.Diagnostics {
+	if len(value) == 0 {
+		delete(s, key)
+		return nil
+	}
+	s[key] = value
+	return nil
+}
+
+func TestPendingTransactionCheck(t *testing.T) {
+	receiptWith := func(finality rpc.TxnFinalityStatus, execution rpc.TxnExecutionStatus) func(*felt.Felt) (*rpc.TransactionReceiptWithBlockInfo, error) {
+		return func(transactionHash *felt.Felt) (*rpc.TransactionReceiptWithBlockInfo, error) {
+			return &rpc.TransactionReceiptWithBlockInfo{TransactionReceipt: rpc.TransactionReceipt{
+				TransactionHash: transactionHash,
+				FinalityStatus:  finality,
+				ExecutionStatus: execution,
+			}}, nil
+		}
+	}
+	notFound := func(*felt.Felt) (*rpc.TransactionReceiptWithBlockInfo, error) {
+		return nil, rpc.ErrHashNotFound
+	}
+
+	tests := []struct {
+		name        string
+		waitFor     WaitFor
+		sentAgo     time.Duration
+		receipt     func(*felt.Felt) (*rpc.TransactionReceiptWithBlockInfo, error)
+		wantStatus  PendingStatus
+		wantReceipt bool
+		wantErr     bool
+	}{
+		{
+			name:        "landed",
+			waitFor:     WaitForAcceptedOnL2,
+			receipt:     receiptWith(rpc.TxnFinalityStatusAcceptedOnL2, rpc.TxnExecutionStatusSUCCEEDED),
+			wantStatus:  PendingLanded,
+			wantReceipt: true,
+		},
+		{
+			name:        "reverted before the awaited status",
+			waitFor:     WaitForAcceptedOnL1,
+			receipt:     receiptWith(rpc.TxnFinalityStatusAcceptedOnL2, rpc.TxnExecutionStatusREVERTED),
+			wantStatus:  PendingLanded,
+			wantReceipt: true,
+		},
+		{
+			name:       "awaited status not reached",
+			waitFor:    WaitForAcceptedOnL1,
+			receipt:    receiptWith(rpc.TxnFinalityStatusAcceptedOnL2, rpc.TxnExecutionStatusSUCCEEDED),
+			wantStatus: PendingWaiting,
+		},
+		{
+			name:       "unknown within grace period",
+			sentAgo:    time.Minute,
+			receipt:    notFound,
+			wantStatus: PendingWaiting,
+		},
+		{
+			name:       "unknown after grace period",
+			sentAgo:    pendingDroppedAfter + time.Minute,
+			receipt:    notFound,
+			wantStatus: PendingDropped,
+		},
+		{
+			name:    "node error",
+			sentAgo: pendingDroppedAfter + time.Minute,
+			receipt: func(*felt.Felt) (*rpc.TransactionReceiptWithBlockInfo, error) {
+				return nil, errors.New("connection refused")
+			},
+			wantStatus: PendingWaiting,
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pending := &PendingTransaction{
+				TransactionHash: "0x123",
+				WaitFor:         tt.waitFor,
+				SentAt:          time.Now().Add(-tt.sentAgo),
+			}
+			client := &fakeRpcProvider{transactionReceipt: tt.receipt}
+
+			receipt, status, err := pending.Check(context.Background(), client)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Check() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if status != tt.wantStatus {
+				t.Errorf("Check() status = %v, want %v", status, tt.wantStatus)
+			}
+			if (receipt != nil) != tt.wantReceipt {
+				t.Errorf("Check() receipt = %v, wantReceipt %v", receipt, tt.wantReceipt)
+			}
+		})
+	}
+}
+
+func TestCheckPendingTransactionClearsPrivateState(t *testing.T) {
+	tests := []struct {
+		name      string
+		sentAgo   time.Duration
+		wantKept  bool
+		wantState PendingStatus
+	}{
+		{name: "waiting", sentAgo: time.Minute, wantKept: true, wantState: PendingWaiting},
+		{name: "dropped", sentAgo: pendingDroppedAfter + time.Minute, wantKept: false, wantState: PendingDropped},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			private := fakePrivateState{}
+			diags := savePendingTransaction(ctx, private, &PendingTransaction{
+				TransactionHash: "0x123",
+				SentAt:          time.Now().Add(-tt.sentAgo),
+			})
+			if diags.HasError() {
+				t.Fatalf("savePendingTransaction() = %v", diags)
+			}
+
+			client := &fakeRpcProvider{
+				transactionReceipt: func(*felt.Felt) (*rpc.TransactionReceiptWithBlockInfo, error) {
+					return nil, rpc.ErrHashNotFound
+				},
+			}
+			_, status, diags := checkPendingTransaction(ctx, client, nil, private)
+			if diags.HasError() {
+				t.Fatalf("checkPendingTransaction() = %v", diags)
+			}
+			if status != tt.wantState {
+				t.Errorf("checkPendingTransaction() status = %v, want %v", status, tt.wantState)
+			}
+			if _, kept := private[pendingTransactionKey]; kept != tt.wantKept {
+				t.Errorf("pending transaction kept = %v, want %v", kept, tt.wantKept)
+			}
+		})
+	}
+}
+
+func TestAddStoppedWaiting(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		err         error
+		wantTainted bool
+	}{
+		{
+			// An interrupted apply must not taint the resource, Terraform
+			// would replace it and send a new transaction
+			name: "interrupted",
+			ctx:  canceled,
+			err:  canceled.Err(),
+		},
+		{
+			name:        "node error",
+			ctx:         context.Background(),
+			err:         errors.New("connection refused"),
+			wantTainted: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var diags diag.Diagnostics
+			addStoppedWaiting(tt.ctx, &diags, "0xabc", tt.err)
+			if len(diags) != 1 {
+				t.Fatalf("addStoppedWaiting() = %v, want one diagnostic", diags)
+			}
+			if diags.HasError() != tt.wantTainted {
+				t.Errorf("addStoppedWaiting() severity = %s, want error %v", diags[0].Severity(), tt.wantTainted)
+			}
+		})
+	}
+}
@@ -0,0 +1,269 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/account"
+	"github.com/NethermindEth/starknet.go/rpc"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	DefaultRebroadcastAfter = 5 * time.Minute
+
+	// maxRebroadcasts bounds how many times a stuck transaction is sent
+	// again, after that the provider only waits.
+	maxRebroadcasts = 3
+
+	// unknownConfirmations is how many status checks, a poll interval
+	// apart, must all find the transaction unknown before it counts as
+	// stuck. With failover a single check may go to a lagging endpoint
+	// that hasn't seen the transaction yet.
+	unknownConfirmations = 3
+)
+
+// transactionStatus returns the status of the transaction, nil when the
+// node doesn't know it.
+func transactionStatus(ctx context.Context, client rpc.RpcProvider, transactionHash *felt.Felt) (*rpc.TxnStatusResp, error) {
+	status, err := client.GetTransactionStatus(ctx, transactionHash)
+	if err != nil {
+		if rpcErr, ok := err.(*rpc.RPCError); ok && rpcErr.Code == rpc.ErrHashNotFound.Code {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return status, nil
+}
+
+// transactionStuck tells whether the node still hasn't got the transaction
+// past the mempool, or doesn't know it at all in unknownConfirmations
+// checks in a row.
+func transactionStuck(ctx context.Context, client rpc.RpcProvider, transactionHash *felt.Felt, pollInterval time.Duration) (bool, error) {
+	for check := 1; ; check++ {
+		status, err := transactionStatus(ctx, client, transactionHash)
+		if err != nil {
+			return false, err
+		}
+		if status != nil {
+			if status.FinalityStatus == rpc.TxnStatus_Rejected {
+				return false, fmt.Errorf("transaction %s was rejected", transactionHash)
+			}
+			return status.FinalityStatus == rpc.TxnStatus_Received, nil
+		}
+		if check >= unknownConfirmations {
+			return true, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return false, ctx.Err()
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// increaseTip doubles the V3 tip, a zero tip becomes 1.
+func increaseTip(tip rpc.U64) (rpc.U64, error) {
+	value, ok := new(big.Int).SetString(string(tip), 0)
+	if !ok {
+		return "", fmt.Errorf("invalid tip %q", tip)
+	}
+	value.Lsh(value, 1)
+	if value.Sign() == 0 {
+		value.SetUint64(1)
+	}
+	if !value.IsUint64() {
+		return "", fmt.Errorf("tip %s overflows u64", value)
+	}
+	return rpc.U64(fmt.Sprintf("%#x", value.Uint64())), nil
+}
+
+// rebroadcast sends the stuck transaction again. Replacements keep the
+// nonce and raise the tip, so at most one of the sent transactions lands.
+func (s *SubmittedTransaction) rebroadcast(ctx context.Context, a *account.Account, replace bool) error {
+	if !replace || s.settings.Version != rpc.TransactionV3 {
+		_, err := a.SendTransaction(ctx, s.Transaction)
+		if rpcErr, ok := err.(*rpc.RPCError); ok && rpcErr.Code == rpc.ErrDuplicateTx.Code {
+			// Still in the mempool of the node
+			return nil
+		}
+		return err
+	}
+
+	settings := s.settings
+	tip, err := increaseTip(settings.Tip)
+	if err != nil {
+		return err
+	}
+	settings.Tip = tip
+
+	tx, err := s.sign(ctx, &settings)
+	if err != nil {
+		return err
+	}
+	response, err := a.SendTransaction(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	s.replaced = append(s.replaced, s.TransactionHash)
+	s.TransactionHash = response.TransactionHash
+	s.Transaction = tx
+	s.settings = settings
+	return nil
+}
+
+// landedTransaction returns the hash of a replaced transaction the node
+// accepted, nil when none of them got through.
+func (s *SubmittedTransaction) landedTransaction(ctx context.Context, client rpc.RpcProvider) *felt.Felt {
+	for _, transactionHash := range s.replaced {
+		status, err := transactionStatus(ctx, client, transactionHash)
+		if err == nil && status != nil &&
+			status.FinalityStatus != rpc.TxnStatus_Received && status.FinalityStatus != rpc.TxnStatus_Rejected {
+			return transactionHash
+		}
+	}
+	return nil
+}
+
+// switchTo makes the replaced transaction that landed the submitted one,
+// the others sent with the same nonce are abandoned.
+func (s *SubmittedTransaction) switchTo(transactionHash *felt.Felt) {
+	replaced := []*felt.Felt{s.TransactionHash}
+	for _, hash := range s.replaced {
+		if !hash.Equal(transactionHash) {
+			replaced = append(replaced, hash)
+		}
+	}
+	s.replaced = replaced
+	s.TransactionHash = transactionHash
+}
+
+// addReplacedWarning reports which transaction landed when a stuck
+// transaction was replaced, and the ones sent with the same nonce that were
+// abandoned.
+func addReplacedWarning(diags *diag.Diagnostics, submitted *SubmittedTransaction) {
+	if len(submitted.replaced) == 0 {
+		return
+	}
+
+	abandoned := make([]string, 0, len(submitted.replaced))
+	for _, transactionHash := range submitted.replaced {
+		abandoned = append(abandoned, transactionHash.String())
+	}
+	diags.AddWarning(
+		"Stuck transaction replaced",
+		fmt.Sprintf(
+			"Transaction %s landed. Transactions %s were sent with the same nonce and are abandoned, they can't land anymore.",
+			submitted.TransactionHash, strings.Join(abandoned, ", "),
+		),
+	)
+}
+
+// WaitForSubmittedTransaction waits for the transaction like
+// WaitForTransaction. When it stays RECEIVED or unknown to the node for
+// options.RebroadcastAfter, the signed transaction is sent again, or with
+// options.ReplaceStuck replaced by one with the same nonce and a higher tip.
+// submitted is updated to the transaction that landed, and onReplaced, when
+// set, is called every time its hash changes, before waiting goes on. When
+// waiting fails the account nonce is resynced, the transaction may have
+// been dropped.
+func WaitForSubmittedTransaction(
+	ctx context.Context,
+	client rpc.RpcProvider,
+	a *account.Account,
+	submitted *SubmittedTransaction,
+	options *WaitOptions,
+	onReplaced func(submitted *SubmittedTransaction),
+) (*rpc.TransactionReceiptWithBlockInfo, error) {
+	if onReplaced == nil {
+		onReplaced = func(*SubmittedTransaction) {}
+	}
+	receipt, err := waitForSubmittedTransaction(ctx, client, a, submitted, options, onReplaced)
+	if err != nil {
+		submitted.resyncNonce(ctx)
+	}
+	return receipt, err
+}
+
+func waitForSubmittedTransaction(
+	ctx context.Context,
+	client rpc.RpcProvider,
+	a *account.Account,
+	submitted *SubmittedTransaction,
+	options *WaitOptions,
+	onReplaced func(submitted *SubmittedTransaction),
+) (*rpc.TransactionReceiptWithBlockInfo, error) {
+	if options.WaitFor == WaitForNone || options.RebroadcastAfter <= 0 {
+		return WaitForTransaction(ctx, client, submitted.TransactionHash, options)
+	}
+
+	rebroadcasts := 0
+	for {
+		waitCtx, cancel := context.WithTimeout(ctx, options.RebroadcastAfter)
+		receipt, err := WaitForTransaction(waitCtx, client, submitted.TransactionHash, options)
+		cancel()
+		if err == nil && rebroadcasts > 0 {
+			tflog.Info(ctx, "stuck transaction landed", map[string]interface{}{
+				"transaction_hash": submitted.TransactionHash.String(),
+				"replaced":         len(submitted.replaced),
+			})
+		}
+		if err == nil || ctx.Err() != nil || !errors.Is(err, context.DeadlineExceeded) {
+			return receipt, err
+		}
+
+		if landed := submitted.landedTransaction(ctx, client); landed != nil {
+			tflog.Info(ctx, "replaced transaction landed", map[string]interface{}{
+				"transaction_hash": landed.String(),
+			})
+			submitted.switchTo(landed)
+			onReplaced(submitted)
+			continue
+		}
+
+		stuck, err := transactionStuck(ctx, client, submitted.TransactionHash, options.PollInterval)
+		if err != nil {
+			return nil, err
+		}
+		if !stuck || rebroadcasts >= maxRebroadcasts {
+			continue
+		}
+		rebroadcasts++
+
+		previousHash := submitted.TransactionHash
+		err = submitted.rebroadcast(ctx, a, options.ReplaceStuck)
+		if err != nil {
+			// The nonce is taken when the transaction landed in the
+			// meantime, waiting goes on to find it out. Otherwise the node
+			// dropped it, so other transactions of the account must not
+			// count on its nonce.
+			tflog.Warn(ctx, "can't send stuck transaction again", map[string]interface{}{
+				"transaction_hash": previousHash.String(),
+				"error":            err.Error(),
+			})
+			submitted.resyncNonce(ctx)
+			continue
+		}
+
+		replaced := !previousHash.Equal(submitted.TransactionHash)
+		tflog.Warn(ctx, "transaction is stuck, sent it again", map[string]interface{}{
+			"transaction_hash": previousHash.String(),
+			"new_hash":         submitted.TransactionHash.String(),
+			"nonce":            submitted.Nonce.String(),
+			"replaced":         replaced,
+			"attempt":          rebroadcasts,
+		})
+		if replaced {
+			onReplaced(submitted)
+		}
+	}
+}
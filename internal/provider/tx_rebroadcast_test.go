@@ -0,0 +1,260 @@
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+func TestTransactionStuck(t *testing.T) {
+	known := func(status rpc.TxnStatus) (*rpc.TxnStatusResp, error) {
+		return &rpc.TxnStatusResp{FinalityStatus: status}, nil
+	}
+	unknown := func() (*rpc.TxnStatusResp, error) {
+		return nil, rpc.ErrHashNotFound
+	}
+
+	tests := []struct {
+		name      string
+		responses []func() (*rpc.TxnStatusResp, error)
+		want      bool
+		wantErr   bool
+		wantCalls int
+	}{
+		{
+			name:      "received",
+			responses: []func() (*rpc.TxnStatusResp, error){func() (*rpc.TxnStatusResp, error) { return known(rpc.TxnStatus_Received) }},
+			want:      true,
+			wantCalls: 1,
+		},
+		{
+			name:      "accepted",
+			responses: []func() (*rpc.TxnStatusResp, error){func() (*rpc.TxnStatusResp, error) { return known(rpc.TxnStatus_Accepted_On_L2) }},
+			want:      false,
+			wantCalls: 1,
+		},
+		{
+			name:      "rejected",
+			responses: []func() (*rpc.TxnStatusResp, error){func() (*rpc.TxnStatusResp, error) { return known(rpc.TxnStatus_Rejected) }},
+			wantErr:   true,
+			wantCalls: 1,
+		},
+		{
+			name:      "unknown in every check",
+			responses: []func() (*rpc.TxnStatusResp, error){unknown, unknown, unknown},
+			want:      true,
+			wantCalls: unknownConfirmations,
+		},
+		{
+			name: "unknown to a lagging endpoint only",
+			responses: []func() (*rpc.TxnStatusResp, error){
+				unknown,
+				func() (*rpc.TxnStatusResp, error) { return known(rpc.TxnStatus_Accepted_On_L2) },
+			},
+			want:      false,
+			wantCalls: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			client := &fakeRpcProvider{
+				transactionStatus: func(*felt.Felt) (*rpc.TxnStatusResp, error) {
+					response := tt.responses[calls]
+					calls++
+					return response()
+				},
+			}
+
+			stuck, err := transactionStuck(context.Background(), client, new(felt.Felt).SetUint64(1), 0)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("transactionStuck() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if stuck != tt.want {
+				t.Errorf("transactionStuck() = %v, want %v", stuck, tt.want)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("transactionStuck() made %d status calls, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestReplacedTransactionLanded(t *testing.T) {
+	first := new(felt.Felt).SetUint64(1)
+	second := new(felt.Felt).SetUint64(2)
+	third := new(felt.Felt).SetUint64(3)
+
+	submitted := &SubmittedTransaction{
+		TransactionHash: third,
+		replaced:        []*felt.Felt{first, second},
+	}
+	client := &fakeRpcProvider{
+		transactionStatus: func(transactionHash *felt.Felt) (*rpc.TxnStatusResp, error) {
+			if transactionHash.Equal(second) {
+				return &rpc.TxnStatusResp{FinalityStatus: rpc.TxnStatus_Accepted_On_L2}, nil
+			}
+			return &rpc.TxnStatusResp{FinalityStatus: rpc.TxnStatus_Received}, nil
+		},
+	}
+
+	landed := submitted.landedTransaction(context.Background(), client)
+	if landed == nil || !landed.Equal(second) {
+		t.Fatalf("landedTransaction() = %v, want %v", landed, second)
+	}
+	submitted.switchTo(landed)
+
+	var diags diag.Diagnostics
+	addReplacedWarning(&diags, submitted)
+	if len(diags) != 1 {
+		t.Fatalf("addReplacedWarning() = %v, want one warning", diags)
+	}
+	detail := diags[0].Detail()
+	want := "Transaction 0x2 landed. Transactions 0x3, 0x1 were sent"
+	if !strings.HasPrefix(detail, want) {
+		t.Errorf("addReplacedWarning() detail = %q, want prefix %q", detail, want)
+	}
+}
+
+func TestIncreaseTip(t *testing.T) {
+	tests := []struct {
+		tip     rpc.U64
+		want    rpc.U64
+		wantErr bool
+	}{
+		{tip: "0x0", want: "0x1"},
+		{tip: "0x1", want: "0x2"},
+		{tip: "0x3b9aca00", want: "0x77359400"},
+		{tip: "0x7fffffffffffffff", want: "0xfffffffffffffffe"},
+		{tip: "0x8000000000000000", wantErr: true},
+		{tip: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.tip), func(t *testing.T) {
+			got, err := increaseTip(tt.tip)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("increaseTip() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("increaseTip() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWaitForSubmittedTransactionRebroadcast(t *testing.T) {
+	stuckHash := new(felt.Felt).SetUint64(0x1)
+	replacementHash := new(felt.Felt).SetUint64(0x2)
+
+	tests := []struct {
+		name    string
+		replace bool
+		// wantHash is the hash that lands and is reported as replaced
+		// while waiting, nil when the same transaction is sent again.
+		wantHash *felt.Felt
+		wantTips []rpc.U64
+	}{
+		{name: "same transaction sent again"},
+		{name: "replaced with a higher tip", replace: true, wantHash: replacementHash, wantTips: []rpc.U64{"0x2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sends := 0
+			client := &fakeRpcProvider{
+				transactionStatus: func(*felt.Felt) (*rpc.TxnStatusResp, error) {
+					return &rpc.TxnStatusResp{FinalityStatus: rpc.TxnStatus_Received}, nil
+				},
+				addInvoke: func(tx rpc.BroadcastInvokeTxnType) (*rpc.AddInvokeTransactionResponse, error) {
+					sends++
+					if tx.(rpc.BroadcastInvokev3Txn).Tip == "0x1" {
+						// Still in the mempool
+						return nil, rpc.ErrDuplicateTx
+					}
+					return &rpc.AddInvokeTransactionResponse{TransactionHash: replacementHash}, nil
+				},
+			}
+			// The transaction lands once it was sent again
+			client.transactionReceipt = func(transactionHash *felt.Felt) (*rpc.TransactionReceiptWithBlockInfo, error) {
+				if sends == 0 {
+					return nil, rpc.ErrHashNotFound
+				}
+				return &rpc.TransactionReceiptWithBlockInfo{TransactionReceipt: rpc.TransactionReceipt{
+					TransactionHash: transactionHash,
+					FinalityStatus:  rpc.TxnFinalityStatusAcceptedOnL2,
+					ExecutionStatus: rpc.TxnExecutionStatusSUCCEEDED,
+				}}, nil
+			}
+
+			tips := []rpc.U64{}
+			sign := func(ctx context.Context, settings *TxSettings) (rpc.BroadcastTxn, error) {
+				tips = append(tips, settings.Tip)
+				return rpc.BroadcastInvokev3Txn{InvokeTxnV3: rpc.InvokeTxnV3{Tip: settings.Tip}}, nil
+			}
+			submitted := &SubmittedTransaction{
+				TransactionHash: stuckHash,
+				Nonce:           new(felt.Felt).SetUint64(5),
+				Transaction:     rpc.BroadcastInvokev3Txn{InvokeTxnV3: rpc.InvokeTxnV3{Tip: "0x1"}},
+				settings:        TxSettings{Version: rpc.TransactionV3, Tip: "0x1"},
+				sign:            sign,
+			}
+
+			// The replacement must be reported before waiting for it, so an
+			// interrupted apply resumes it
+			var reported *felt.Felt
+			onReplaced := func(submitted *SubmittedTransaction) {
+				if sends == 0 {
+					t.Error("onReplaced() called before the replacement was sent")
+				}
+				reported = submitted.TransactionHash
+			}
+
+			receipt, err := WaitForSubmittedTransaction(
+				context.Background(),
+				client,
+				newFakeAccount(t, client, new(felt.Felt).SetUint64(0x1234)),
+				submitted,
+				&WaitOptions{
+					WaitFor:          WaitForAcceptedOnL2,
+					PollInterval:     time.Millisecond,
+					RebroadcastAfter: 20 * time.Millisecond,
+					ReplaceStuck:     tt.replace,
+				},
+				onReplaced,
+			)
+			if err != nil {
+				t.Fatalf("WaitForSubmittedTransaction() error = %v", err)
+			}
+			if sends != 1 {
+				t.Errorf("WaitForSubmittedTransaction() sent %d transactions, want 1", sends)
+			}
+
+			wantHash := stuckHash
+			if tt.wantHash != nil {
+				wantHash = tt.wantHash
+				if reported == nil || !reported.Equal(tt.wantHash) {
+					t.Errorf("onReplaced() got %v, want %s", reported, tt.wantHash)
+				}
+				if len(submitted.replaced) != 1 || !submitted.replaced[0].Equal(stuckHash) {
+					t.Errorf("replaced = %v, want [%s]", submitted.replaced, stuckHash)
+				}
+			} else if reported != nil {
+				t.Errorf("onReplaced() got %s, want no call", reported)
+			}
+			if !receipt.TransactionHash.Equal(wantHash) || !submitted.TransactionHash.Equal(wantHash) {
+				t.Errorf("landed %s, submitted %s, want %s", receipt.TransactionHash, submitted.TransactionHash, wantHash)
+			}
+			if len(tips) != len(tt.wantTips) || (len(tips) > 0 && tips[0] != tt.wantTips[0]) {
+				t.Errorf("signed tips %v, want %v", tips, tt.wantTips)
+			}
+		})
+	}
+}
@@ -0,0 +1,245 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/objectplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	framework_types "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var actualFeeAttrTypes = map[string]attr.Type{
+	"amount": framework_types.StringType,
+	"unit":   framework_types.StringType,
+}
+
+// TransactionReceiptModel describes the receipt attributes shared by the
+// transaction sending resources.
+type TransactionReceiptModel struct {
+	TransactionHash framework_types.String `tfsdk:"transaction_hash"`
+	BlockNumber     framework_types.Int64  `tfsdk:"block_number"`
+	BlockHash       framework_types.String `tfsdk:"block_hash"`
+	ActualFee       framework_types.Object `tfsdk:"actual_fee"`
+	FinalityStatus  framework_types.String `tfsdk:"finality_status"`
+	ExecutionStatus framework_types.String `tfsdk:"execution_status"`
+	RevertReason    framework_types.String `tfsdk:"revert_reason"`
+	EstimatedFee    framework_types.Object `tfsdk:"estimated_fee"`
+}
+
+// transactionReceiptAttributes returns the schema of TransactionReceiptModel.
+func transactionReceiptAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"transaction_hash": schema.StringAttribute{
+			Computed:            true,
+			MarkdownDescription: "Transaction hash",
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.UseStateForUnknown(),
+			},
+		},
+		"block_number": schema.Int64Attribute{
+			Computed:            true,
+			MarkdownDescription: "Number of the block including the transaction",
+			PlanModifiers: []planmodifier.Int64{
+				int64planmodifier.UseStateForUnknown(),
+			},
+		},
+		"block_hash": schema.StringAttribute{
+			Computed:            true,
+			MarkdownDescription: "Hash of the block including the transaction",
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.UseStateForUnknown(),
+			},
+		},
+		"actual_fee": schema.SingleNestedAttribute{
+			Computed:            true,
+			MarkdownDescription: "Fee charged for the transaction",
+			Attributes: map[string]schema.Attribute{
+				"amount": schema.StringAttribute{
+					Computed:            true,
+					MarkdownDescription: "Fee amount",
+				},
+				"unit": schema.StringAttribute{
+					Computed:            true,
+					MarkdownDescription: "Fee unit, `WEI` or `FRI`",
+				},
+			},
+			PlanModifiers: []planmodifier.Object{
+				objectplanmodifier.UseStateForUnknown(),
+			},
+		},
+		"finality_status": schema.StringAttribute{
+			Computed: true,
+			MarkdownDescription: "Finality status of the transaction recorded at apply time, or on the refresh that found " +
+				"a pending transaction landed. It isn't updated afterwards, e.g. when the block is accepted on L1.",
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.UseStateForUnknown(),
+			},
+		},
+		"execution_status": schema.StringAttribute{
+			Computed:            true,
+			MarkdownDescription: "Execution status of the transaction",
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.UseStateForUnknown(),
+			},
+		},
+		"revert_reason": schema.StringAttribute{
+			Computed:            true,
+			MarkdownDescription: "Revert reason when the transaction was reverted",
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.UseStateForUnknown(),
+			},
+		},
+		"estimated_fee": schema.SingleNestedAttribute{
+			Computed:            true,
+			MarkdownDescription: "Fee estimated by simulating the transaction at plan time, null when inputs weren't known",
+			Attributes: map[string]schema.Attribute{
+				"amount": schema.StringAttribute{
+					Computed:            true,
+					MarkdownDescription: "Fee amount",
+				},
+				"unit": schema.StringAttribute{
+					Computed:            true,
+					MarkdownDescription: "Fee unit, `WEI` or `FRI`",
+				},
+			},
+			PlanModifiers: []planmodifier.Object{
+				objectplanmodifier.UseStateForUnknown(),
+			},
+		},
+	}
+}
+
+// withTransactionReceipt adds the TransactionReceiptModel attributes to the
+// resource attributes.
+func withTransactionReceipt(attributes map[string]schema.Attribute) map[string]schema.Attribute {
+	for name, attribute := range transactionReceiptAttributes() {
+		attributes[name] = attribute
+	}
+	return attributes
+}
+
+// SetReceipt fills the model from the transaction receipt.
+func (m *TransactionReceiptModel) SetReceipt(receipt *rpc.TransactionReceiptWithBlockInfo) {
+	m.TransactionHash = framework_types.StringValue(receipt.TransactionHash.String())
+	m.BlockNumber = framework_types.Int64Value(int64(receipt.BlockNumber))
+	m.BlockHash = framework_types.StringNull()
+	if receipt.BlockHash != nil {
+		m.BlockHash = framework_types.StringValue(receipt.BlockHash.String())
+	}
+	m.FinalityStatus = framework_types.StringValue(string(receipt.FinalityStatus))
+	m.ExecutionStatus = framework_types.StringValue(string(receipt.ExecutionStatus))
+	m.RevertReason = framework_types.StringNull()
+	if receipt.RevertReason != "" {
+		m.RevertReason = framework_types.StringValue(decodeRevertReason(receipt.RevertReason))
+	}
+
+	amount := framework_types.StringNull()
+	if receipt.ActualFee.Amount != nil {
+		amount = framework_types.StringValue(receipt.ActualFee.Amount.String())
+	}
+	m.ActualFee = framework_types.ObjectValueMust(actualFeeAttrTypes, map[string]attr.Value{
+		"amount": amount,
+		"unit":   framework_types.StringValue(string(receipt.ActualFee.Unit)),
+	})
+}
+
+// SetEstimatedFee fills the estimated fee from the simulation result.
+func (m *TransactionReceiptModel) SetEstimatedFee(estimation *rpc.FeeEstimation) {
+	amount := framework_types.StringNull()
+	if estimation.OverallFee != nil {
+		amount = framework_types.StringValue(estimation.OverallFee.String())
+	}
+	m.EstimatedFee = framework_types.ObjectValueMust(actualFeeAttrTypes, map[string]attr.Value{
+		"amount": amount,
+		"unit":   framework_types.StringValue(string(estimation.FeeUnit)),
+	})
+}
+
+// ResolveEstimatedFee sets the estimated fee to null when it wasn't
+// simulated at plan time.
+func (m *TransactionReceiptModel) ResolveEstimatedFee() {
+	if m.EstimatedFee.IsUnknown() {
+		m.EstimatedFee = framework_types.ObjectNull(actualFeeAttrTypes)
+	}
+}
+
+// SetPendingReceipt is used when the resource doesn't wait for the receipt,
+// only the transaction hash is known.
+func (m *TransactionReceiptModel) SetPendingReceipt(transactionHash *felt.Felt) {
+	m.SetNoReceipt()
+	m.TransactionHash = framework_types.StringValue(transactionHash.String())
+}
+
+// SetNoReceipt marks the receipt attributes as not available, e.g. when no
+// transaction was sent.
+func (m *TransactionReceiptModel) SetNoReceipt() {
+	m.TransactionHash = framework_types.StringNull()
+	m.BlockNumber = framework_types.Int64Null()
+	m.BlockHash = framework_types.StringNull()
+	m.ActualFee = framework_types.ObjectNull(actualFeeAttrTypes)
+	m.FinalityStatus = framework_types.StringNull()
+	m.ExecutionStatus = framework_types.StringNull()
+	m.RevertReason = framework_types.StringNull()
+}
+
+var revertReasonFeltRegexp = regexp.MustCompile(`0x[0-9a-fA-F]+( \('[^']*'\))?`)
+
+// decodeShortString decodes a felt holding a Cairo short string. It returns
+// false when the value doesn't look like printable text.
+func decodeShortString(value string) (string, bool) {
+	f, err := new(felt.Felt).SetString(value)
+	if err != nil {
+		return "", false
+	}
+
+	bytes := f.Bytes()
+	text := strings.TrimLeft(string(bytes[:]), "\x00")
+	if text == "" {
+		return "", false
+	}
+	for _, r := range text {
+		if r > unicode.MaxASCII || !unicode.IsPrint(r) {
+			return "", false
+		}
+	}
+	return text, true
+}
+
+// decodeRevertReason appends the decoded short string to the felts found in
+// the revert reason, e.g. `0x4e6f7420656e6f7567682062616c616e6365 ('Not enough balance')`.
+// Felts the node already decoded are left as is.
+func decodeRevertReason(reason string) string {
+	return revertReasonFeltRegexp.ReplaceAllStringFunc(reason, func(match string) string {
+		if strings.Contains(match, " ('") {
+			return match
+		}
+		text, ok := decodeShortString(match)
+		if !ok {
+			return match
+		}
+		return fmt.Sprintf("%s ('%s')", match, text)
+	})
+}
+
+// RevertError returns an error with the decoded revert reason when the
+// transaction was reverted, nil otherwise.
+func RevertError(receipt *rpc.TransactionReceiptWithBlockInfo) error {
+	if receipt == nil || receipt.ExecutionStatus != rpc.TxnExecutionStatusREVERTED {
+		return nil
+	}
+	return fmt.Errorf(
+		"transaction %s was reverted: %s",
+		receipt.TransactionHash,
+		decodeRevertReason(receipt.RevertReason),
+	)
+}
@@ -0,0 +1,124 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+)
+
+func TestDecodeRevertReason(t *testing.T) {
+	tests := []struct {
+		name   string
+		reason string
+		want   string
+	}{
+		{
+			name:   "short string",
+			reason: "Execution failed. Failure reason: 0x4e6f7420656e6f7567682062616c616e6365.",
+			want:   "Execution failed. Failure reason: 0x4e6f7420656e6f7567682062616c616e6365 ('Not enough balance').",
+		},
+		{
+			name:   "already decoded",
+			reason: "Failure reason: 0x4661696c6564 ('Failed').",
+			want:   "Failure reason: 0x4661696c6564 ('Failed').",
+		},
+		{
+			name:   "address left as is",
+			reason: "Error in the called contract (0x1):",
+			want:   "Error in the called contract (0x1):",
+		},
+		{
+			name:   "plain text",
+			reason: "Out of gas",
+			want:   "Out of gas",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := decodeRevertReason(tt.reason); got != tt.want {
+				t.Errorf("decodeRevertReason() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRevertError(t *testing.T) {
+	receipt := func(status rpc.TxnExecutionStatus, reason string) *rpc.TransactionReceiptWithBlockInfo {
+		return &rpc.TransactionReceiptWithBlockInfo{TransactionReceipt: rpc.TransactionReceipt{
+			TransactionHash: new(felt.Felt).SetUint64(0xabc),
+			ExecutionStatus: status,
+			RevertReason:    reason,
+		}}
+	}
+
+	tests := []struct {
+		name    string
+		receipt *rpc.TransactionReceiptWithBlockInfo
+		want    string
+	}{
+		{name: "no receipt"},
+		{name: "succeeded", receipt: receipt(rpc.TxnExecutionStatusSUCCEEDED, "")},
+		{
+			name:    "reverted",
+			receipt: receipt(rpc.TxnExecutionStatusREVERTED, "0x4661696c6564"),
+			want:    "transaction 0xabc was reverted: 0x4661696c6564 ('Failed')",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := RevertError(tt.receipt)
+			if tt.want == "" {
+				if err != nil {
+					t.Fatalf("RevertError() = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("RevertError() = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestTransactionReceiptModelSetReceipt(t *testing.T) {
+	var model TransactionReceiptModel
+	model.SetReceipt(&rpc.TransactionReceiptWithBlockInfo{
+		TransactionReceipt: rpc.TransactionReceipt{
+			TransactionHash: new(felt.Felt).SetUint64(0xabc),
+			ActualFee:       rpc.FeePayment{Amount: new(felt.Felt).SetUint64(0x64), Unit: rpc.UnitStrk},
+			ExecutionStatus: rpc.TxnExecutionStatusREVERTED,
+			FinalityStatus:  rpc.TxnFinalityStatusAcceptedOnL2,
+			RevertReason:    "0x4661696c6564",
+		},
+		BlockHash:   new(felt.Felt).SetUint64(0xdef),
+		BlockNumber: 42,
+	})
+
+	if got := model.TransactionHash.ValueString(); got != "0xabc" {
+		t.Errorf("transaction_hash = %s", got)
+	}
+	if got := model.BlockNumber.ValueInt64(); got != 42 {
+		t.Errorf("block_number = %d", got)
+	}
+	if got := model.BlockHash.ValueString(); got != "0xdef" {
+		t.Errorf("block_hash = %s", got)
+	}
+	if got := model.RevertReason.ValueString(); got != "0x4661696c6564 ('Failed')" {
+		t.Errorf("revert_reason = %s", got)
+	}
+	if got := model.ActualFee.Attributes()["amount"].String(); got != `"0x64"` {
+		t.Errorf("actual_fee.amount = %s", got)
+	}
+
+	// A pending receipt keeps the hash only
+	model.SetPendingReceipt(new(felt.Felt).SetUint64(0x123))
+	if got := model.TransactionHash.ValueString(); got != "0x123" {
+		t.Errorf("transaction_hash = %s", got)
+	}
+	if !model.BlockNumber.IsNull() || !model.ActualFee.IsNull() || !model.RevertReason.IsNull() {
+		t.Errorf("pending receipt attributes = %+v, want null", model)
+	}
+}
@@ -0,0 +1,382 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	framework_types "github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/baitcode/terraform-provider-starknet/internal/provider/types"
+)
+
+const DefaultTransactionVersion = rpc.TransactionV3
+
+// TxSettings holds the fee and version options used to build a transaction.
+type TxSettings struct {
+	Version rpc.TransactionVersion
+	// Nonce is reserved by the NonceManager, the nonce is read from the
+	// node when it's nil.
+	Nonce *felt.Felt
+	// ExtraFeeMarginPercent is added to the margin applied to estimated
+	// fees, it's raised when the node rejects the fee as too low.
+	ExtraFeeMarginPercent int64
+
+	// ResourceBounds overrides the estimated V3 resource bounds when set.
+	ResourceBounds *rpc.ResourceBoundsMapping
+	Tip            rpc.U64
+	NonceDAMode    rpc.DataAvailabilityMode
+	FeeDAMode      rpc.DataAvailabilityMode
+	PaymasterData  []*felt.Felt
+}
+
+// TransactionSettingsModel describes the fee attributes shared by the
+// transaction sending resources.
+type TransactionSettingsModel struct {
+	Account            framework_types.String `tfsdk:"account"`
+	TransactionVersion framework_types.String `tfsdk:"transaction_version"`
+	ResourceBounds     *ResourceBoundsModel   `tfsdk:"resource_bounds"`
+	Tip                framework_types.Int64  `tfsdk:"tip"`
+	NonceDAMode        framework_types.String `tfsdk:"nonce_data_availability_mode"`
+	FeeDAMode          framework_types.String `tfsdk:"fee_data_availability_mode"`
+	PaymasterData      framework_types.List   `tfsdk:"paymaster_data"`
+}
+
+type ResourceBoundsModel struct {
+	L1Gas     *ResourceBoundModel `tfsdk:"l1_gas"`
+	L2Gas     *ResourceBoundModel `tfsdk:"l2_gas"`
+	L1DataGas *ResourceBoundModel `tfsdk:"l1_data_gas"`
+}
+
+type ResourceBoundModel struct {
+	MaxAmount       framework_types.Int64  `tfsdk:"max_amount"`
+	MaxPricePerUnit framework_types.String `tfsdk:"max_price_per_unit"`
+}
+
+func resourceBoundAttribute(description string) schema.SingleNestedAttribute {
+	return schema.SingleNestedAttribute{
+		Required:            true,
+		MarkdownDescription: description,
+		Attributes: map[string]schema.Attribute{
+			"max_amount": schema.Int64Attribute{
+				Required:            true,
+				MarkdownDescription: "Max amount of the resource that can be used",
+			},
+			"max_price_per_unit": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "Max price per unit of the resource in FRI",
+			},
+		},
+	}
+}
+
+// transactionSettingsAttributes returns the schema of TransactionSettingsModel.
+func transactionSettingsAttributes() map[string]schema.Attribute {
+	l1DataGas := resourceBoundAttribute(
+		"L1 data gas bounds. Transactions of spec 0.7, the only supported one, have no separate L1 data gas bound, " +
+			"the amount is converted to L1 gas at the `l1_gas` price and added to the `l1_gas` bound.",
+	)
+	l1DataGas.Required = false
+	l1DataGas.Optional = true
+
+	return map[string]schema.Attribute{
+		"account": schema.StringAttribute{
+			Optional:            true,
+			MarkdownDescription: "Name of the provider `account` signing the transaction. Defaults to the provider account.",
+		},
+		"transaction_version": schema.StringAttribute{
+			Optional:            true,
+			MarkdownDescription: "Transaction version, `v2` or `v3`. Defaults to the provider setting.",
+		},
+		"resource_bounds": schema.SingleNestedAttribute{
+			Optional:            true,
+			MarkdownDescription: "V3 resource bounds. Estimated from the node when omitted.",
+			Attributes: map[string]schema.Attribute{
+				"l1_gas":      resourceBoundAttribute("L1 gas bounds"),
+				"l2_gas":      resourceBoundAttribute("L2 gas bounds"),
+				"l1_data_gas": l1DataGas,
+			},
+		},
+		"tip": schema.Int64Attribute{
+			Optional:            true,
+			MarkdownDescription: "V3 transaction tip, a u64 in FRI per L2 gas",
+		},
+		"nonce_data_availability_mode": schema.StringAttribute{
+			Optional:            true,
+			MarkdownDescription: "V3 nonce data availability mode, `L1` or `L2`. Defaults to `L1`.",
+		},
+		"fee_data_availability_mode": schema.StringAttribute{
+			Optional:            true,
+			MarkdownDescription: "V3 fee data availability mode, `L1` or `L2`. Defaults to `L1`.",
+		},
+		"paymaster_data": schema.ListAttribute{
+			ElementType:         types.FeltType{},
+			Optional:            true,
+			MarkdownDescription: "V3 paymaster data",
+		},
+	}
+}
+
+// withTransactionSettings adds the TransactionSettingsModel attributes to
+// the resource attributes.
+func withTransactionSettings(attributes map[string]schema.Attribute) map[string]schema.Attribute {
+	for name, attribute := range transactionSettingsAttributes() {
+		attributes[name] = attribute
+	}
+	return attributes
+}
+
+func (m *ResourceBoundModel) isKnown() bool {
+	return m == nil || (!m.MaxAmount.IsUnknown() && !m.MaxPricePerUnit.IsUnknown())
+}
+
+// IsKnown tells whether all settings are known, e.g. at plan time.
+func (m TransactionSettingsModel) IsKnown() bool {
+	if m.Account.IsUnknown() || m.TransactionVersion.IsUnknown() || m.Tip.IsUnknown() ||
+		m.NonceDAMode.IsUnknown() || m.FeeDAMode.IsUnknown() || m.PaymasterData.IsUnknown() {
+		return false
+	}
+	for _, value := range m.PaymasterData.Elements() {
+		if value.IsUnknown() {
+			return false
+		}
+	}
+	if m.ResourceBounds != nil && (!m.ResourceBounds.L1Gas.isKnown() ||
+		!m.ResourceBounds.L2Gas.isKnown() || !m.ResourceBounds.L1DataGas.isKnown()) {
+		return false
+	}
+	return true
+}
+
+func parseTransactionVersion(version string) (rpc.TransactionVersion, error) {
+	switch version {
+	case "v2", "2":
+		return rpc.TransactionV2, nil
+	case "v3", "3":
+		return rpc.TransactionV3, nil
+	}
+	return "", fmt.Errorf("unsupported transaction version %q, expected v2 or v3", version)
+}
+
+func parseDataAvailabilityMode(mode string) (rpc.DataAvailabilityMode, error) {
+	switch mode {
+	case "", string(rpc.DAModeL1):
+		return rpc.DAModeL1, nil
+	case string(rpc.DAModeL2):
+		return rpc.DAModeL2, nil
+	}
+	return "", fmt.Errorf("unsupported data availability mode %q, expected L1 or L2", mode)
+}
+
+// parseU128 parses a decimal or 0x prefixed u128.
+func parseU128(value string) (*big.Int, error) {
+	number, ok := new(big.Int).SetString(strings.TrimSpace(value), 0)
+	if !ok {
+		return nil, fmt.Errorf("%q is not a number", value)
+	}
+	if number.Sign() < 0 {
+		return nil, fmt.Errorf("%s is negative", value)
+	}
+	if number.Cmp(maxU128) > 0 {
+		return nil, fmt.Errorf("%s overflows u128", value)
+	}
+	return number, nil
+}
+
+// validate checks the known values of the bound. max_amount is an Int64,
+// so only negative values are out of the u64 range.
+func (m *ResourceBoundModel) validate(attributePath path.Path, diags *diag.Diagnostics) {
+	if m == nil {
+		return
+	}
+	if !m.MaxAmount.IsNull() && !m.MaxAmount.IsUnknown() && m.MaxAmount.ValueInt64() < 0 {
+		diags.AddAttributeError(
+			attributePath.AtName("max_amount"),
+			"Invalid max_amount",
+			fmt.Sprintf("max_amount is a u64 and can't be negative, got %d", m.MaxAmount.ValueInt64()),
+		)
+	}
+	if !m.MaxPricePerUnit.IsNull() && !m.MaxPricePerUnit.IsUnknown() {
+		if _, err := parseU128(m.MaxPricePerUnit.ValueString()); err != nil {
+			diags.AddAttributeError(
+				attributePath.AtName("max_price_per_unit"),
+				"Invalid max_price_per_unit",
+				fmt.Sprintf("max_price_per_unit must be a u128: %s", err),
+			)
+		}
+	}
+}
+
+// Validate checks the known fee settings fit the transaction fields, so
+// invalid values fail at plan time instead of being rejected by the node.
+func (m TransactionSettingsModel) Validate() diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if !m.Tip.IsNull() && !m.Tip.IsUnknown() && m.Tip.ValueInt64() < 0 {
+		diags.AddAttributeError(
+			path.Root("tip"),
+			"Invalid tip",
+			fmt.Sprintf("tip is a u64 and can't be negative, got %d", m.Tip.ValueInt64()),
+		)
+	}
+
+	if m.ResourceBounds != nil {
+		bounds := path.Root("resource_bounds")
+		m.ResourceBounds.L1Gas.validate(bounds.AtName("l1_gas"), &diags)
+		m.ResourceBounds.L2Gas.validate(bounds.AtName("l2_gas"), &diags)
+		m.ResourceBounds.L1DataGas.validate(bounds.AtName("l1_data_gas"), &diags)
+	}
+
+	return diags
+}
+
+func (m ResourceBoundModel) values() (maxAmount *big.Int, maxPricePerUnit *big.Int, err error) {
+	if m.MaxAmount.ValueInt64() < 0 {
+		return nil, nil, fmt.Errorf("max_amount can't be negative")
+	}
+	maxPricePerUnit, err = parseU128(m.MaxPricePerUnit.ValueString())
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid max_price_per_unit: %w", err)
+	}
+	return big.NewInt(m.MaxAmount.ValueInt64()), maxPricePerUnit, nil
+}
+
+func (m ResourceBoundModel) toResourceBounds() (rpc.ResourceBounds, error) {
+	maxAmount, maxPricePerUnit, err := m.values()
+	if err != nil {
+		return rpc.ResourceBounds{}, err
+	}
+	return newResourceBounds(maxAmount, maxPricePerUnit)
+}
+
+// toResourceBounds converts the model into the resource bounds of a spec
+// 0.7 transaction, the L1 data gas bound is added to the L1 gas bound.
+func (m ResourceBoundsModel) toResourceBounds() (*rpc.ResourceBoundsMapping, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	bounds := &rpc.ResourceBoundsMapping{
+		L1Gas: rpc.ResourceBounds{MaxAmount: rpc.U64("0x0"), MaxPricePerUnit: rpc.U128("0x0")},
+		L2Gas: rpc.ResourceBounds{MaxAmount: rpc.U64("0x0"), MaxPricePerUnit: rpc.U128("0x0")},
+	}
+
+	if m.L2Gas != nil {
+		l2Gas, err := m.L2Gas.toResourceBounds()
+		if err != nil {
+			diags.AddError("Invalid resource_bounds.l2_gas", err.Error())
+			return nil, diags
+		}
+		bounds.L2Gas = l2Gas
+	}
+
+	if m.L1Gas == nil {
+		if m.L1DataGas != nil {
+			diags.AddError("Invalid resource_bounds.l1_data_gas", "l1_data_gas requires l1_gas, data gas is paid at the L1 gas price")
+			return nil, diags
+		}
+		return bounds, diags
+	}
+
+	gasAmount, gasPrice, err := m.L1Gas.values()
+	if err != nil {
+		diags.AddError("Invalid resource_bounds.l1_gas", err.Error())
+		return nil, diags
+	}
+	if m.L1DataGas != nil {
+		dataGasAmount, dataGasPrice, err := m.L1DataGas.values()
+		if err != nil {
+			diags.AddError("Invalid resource_bounds.l1_data_gas", err.Error())
+			return nil, diags
+		}
+		gasAmount, err = withDataGas(gasAmount, gasPrice, dataGasAmount, dataGasPrice)
+		if err != nil {
+			diags.AddError("Invalid resource_bounds.l1_data_gas", err.Error())
+			return nil, diags
+		}
+	}
+	bounds.L1Gas, err = newResourceBounds(gasAmount, gasPrice)
+	if err != nil {
+		diags.AddError("Invalid resource_bounds.l1_gas", err.Error())
+		return nil, diags
+	}
+
+	return bounds, diags
+}
+
+// TxSettings converts the model into TxSettings, falling back to the
+// provider's transaction version when the resource doesn't set one.
+func (m TransactionSettingsModel) TxSettings(
+	ctx context.Context,
+	defaultVersion rpc.TransactionVersion,
+) (*TxSettings, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	settings := &TxSettings{
+		Version:       defaultVersion,
+		Tip:           rpc.U64("0x0"),
+		PaymasterData: []*felt.Felt{},
+	}
+
+	if settings.Version == "" {
+		settings.Version = DefaultTransactionVersion
+	}
+
+	if !m.TransactionVersion.IsNull() {
+		version, err := parseTransactionVersion(m.TransactionVersion.ValueString())
+		if err != nil {
+			diags.AddError("Invalid transaction_version", err.Error())
+			return nil, diags
+		}
+		settings.Version = version
+	}
+
+	diags.Append(m.Validate()...)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	if !m.Tip.IsNull() {
+		settings.Tip = rpc.U64(fmt.Sprintf("%#x", m.Tip.ValueInt64()))
+	}
+
+	nonceDAMode, err := parseDataAvailabilityMode(m.NonceDAMode.ValueString())
+	if err != nil {
+		diags.AddError("Invalid nonce_data_availability_mode", err.Error())
+		return nil, diags
+	}
+	settings.NonceDAMode = nonceDAMode
+
+	feeDAMode, err := parseDataAvailabilityMode(m.FeeDAMode.ValueString())
+	if err != nil {
+		diags.AddError("Invalid fee_data_availability_mode", err.Error())
+		return nil, diags
+	}
+	settings.FeeDAMode = feeDAMode
+
+	if !m.PaymasterData.IsNull() {
+		var paymasterData []types.Felt
+		diags.Append(m.PaymasterData.ElementsAs(ctx, &paymasterData, false)...)
+		if diags.HasError() {
+			return nil, diags
+		}
+		for _, value := range paymasterData {
+			settings.PaymasterData = append(settings.PaymasterData, value.Felt)
+		}
+	}
+
+	if m.ResourceBounds != nil {
+		bounds, boundsDiags := m.ResourceBounds.toResourceBounds()
+		diags.Append(boundsDiags...)
+		if diags.HasError() {
+			return nil, diags
+		}
+		settings.ResourceBounds = bounds
+	}
+
+	return settings, diags
+}
@@ -0,0 +1,196 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+
+	framework_types "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestResourceBoundsFromEstimation(t *testing.T) {
+	tests := []struct {
+		name        string
+		estimation  rpc.FeeEstimation
+		extraMargin int64
+		wantL1Gas   rpc.ResourceBounds
+		wantErr     bool
+	}{
+		{
+			name: "l1 gas only",
+			estimation: rpc.FeeEstimation{
+				GasConsumed: new(felt.Felt).SetUint64(100),
+				GasPrice:    new(felt.Felt).SetUint64(10),
+			},
+			// 100 * 1.5 + 1 at 10 * 1.5
+			wantL1Gas: rpc.ResourceBounds{MaxAmount: "0x97", MaxPricePerUnit: "0xf"},
+		},
+		{
+			name: "data gas is converted to l1 gas",
+			estimation: rpc.FeeEstimation{
+				GasConsumed:     new(felt.Felt).SetUint64(100),
+				GasPrice:        new(felt.Felt).SetUint64(10),
+				DataGasConsumed: new(felt.Felt).SetUint64(10),
+				DataGasPrice:    new(felt.Felt).SetUint64(3),
+			},
+			// 150 + ceil(15 * 4 / 15) + 1 at 15
+			wantL1Gas: rpc.ResourceBounds{MaxAmount: "0x9b", MaxPricePerUnit: "0xf"},
+		},
+		{
+			name: "extra margin",
+			estimation: rpc.FeeEstimation{
+				GasConsumed: new(felt.Felt).SetUint64(100),
+				GasPrice:    new(felt.Felt).SetUint64(10),
+			},
+			extraMargin: 50,
+			// 100 * 2 + 1 at 10 * 2
+			wantL1Gas: rpc.ResourceBounds{MaxAmount: "0xc9", MaxPricePerUnit: "0x14"},
+		},
+		{
+			name: "zero gas price",
+			estimation: rpc.FeeEstimation{
+				GasConsumed: new(felt.Felt).SetUint64(100),
+				GasPrice:    new(felt.Felt),
+			},
+			wantErr: true,
+		},
+		{
+			name: "amount overflows u64",
+			estimation: rpc.FeeEstimation{
+				GasConsumed: new(felt.Felt).SetUint64(^uint64(0)),
+				GasPrice:    new(felt.Felt).SetUint64(1),
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings := &TxSettings{ExtraFeeMarginPercent: tt.extraMargin}
+			bounds, err := resourceBoundsFromEstimation([]rpc.FeeEstimation{tt.estimation}, settings)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resourceBoundsFromEstimation() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if bounds.L1Gas != tt.wantL1Gas {
+				t.Errorf("L1Gas = %+v, want %+v", bounds.L1Gas, tt.wantL1Gas)
+			}
+			if bounds.L2Gas.MaxAmount != "0x0" || bounds.L2Gas.MaxPricePerUnit != "0x0" {
+				t.Errorf("L2Gas = %+v, want zero", bounds.L2Gas)
+			}
+		})
+	}
+}
+
+func TestTransactionSettingsModelValidate(t *testing.T) {
+	bound := func(amount int64, price string) *ResourceBoundModel {
+		return &ResourceBoundModel{
+			MaxAmount:       framework_types.Int64Value(amount),
+			MaxPricePerUnit: framework_types.StringValue(price),
+		}
+	}
+
+	tests := []struct {
+		name    string
+		model   TransactionSettingsModel
+		wantErr bool
+	}{
+		{
+			name:  "empty",
+			model: TransactionSettingsModel{},
+		},
+		{
+			name: "valid",
+			model: TransactionSettingsModel{
+				Tip: framework_types.Int64Value(10),
+				ResourceBounds: &ResourceBoundsModel{
+					L1Gas:     bound(1000, "0x5af3107a4000"),
+					L2Gas:     bound(0, "0"),
+					L1DataGas: bound(100, "340282366920938463463374607431768211455"),
+				},
+			},
+		},
+		{
+			name:    "negative tip",
+			model:   TransactionSettingsModel{Tip: framework_types.Int64Value(-1)},
+			wantErr: true,
+		},
+		{
+			name:  "unknown tip",
+			model: TransactionSettingsModel{Tip: framework_types.Int64Unknown()},
+		},
+		{
+			name: "negative max_amount",
+			model: TransactionSettingsModel{ResourceBounds: &ResourceBoundsModel{
+				L1Gas: bound(-1, "1"),
+				L2Gas: bound(0, "0"),
+			}},
+			wantErr: true,
+		},
+		{
+			name: "negative max_price_per_unit",
+			model: TransactionSettingsModel{ResourceBounds: &ResourceBoundsModel{
+				L1Gas: bound(1, "-1"),
+				L2Gas: bound(0, "0"),
+			}},
+			wantErr: true,
+		},
+		{
+			name: "max_price_per_unit overflows u128",
+			model: TransactionSettingsModel{ResourceBounds: &ResourceBoundsModel{
+				L1Gas:     bound(1, "1"),
+				L2Gas:     bound(0, "0"),
+				L1DataGas: bound(1, "0x100000000000000000000000000000000"),
+			}},
+			wantErr: true,
+		},
+		{
+			name: "max_price_per_unit not a number",
+			model: TransactionSettingsModel{ResourceBounds: &ResourceBoundsModel{
+				L1Gas: bound(1, "cheap"),
+				L2Gas: bound(0, "0"),
+			}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := tt.model.Validate()
+			if diags.HasError() != tt.wantErr {
+				t.Fatalf("Validate() = %v, wantErr %v", diags, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestResourceBoundsModelToResourceBounds(t *testing.T) {
+	model := ResourceBoundsModel{
+		L1Gas: &ResourceBoundModel{
+			MaxAmount:       framework_types.Int64Value(1000),
+			MaxPricePerUnit: framework_types.StringValue("100"),
+		},
+		L2Gas: &ResourceBoundModel{
+			MaxAmount:       framework_types.Int64Value(0),
+			MaxPricePerUnit: framework_types.StringValue("0"),
+		},
+		L1DataGas: &ResourceBoundModel{
+			MaxAmount:       framework_types.Int64Value(10),
+			MaxPricePerUnit: framework_types.StringValue("15"),
+		},
+	}
+
+	bounds, diags := model.toResourceBounds()
+	if diags.HasError() {
+		t.Fatalf("toResourceBounds() = %v", diags)
+	}
+
+	// 1000 + ceil(10 * 15 / 100) at 100
+	want := rpc.ResourceBounds{MaxAmount: "0x3ea", MaxPricePerUnit: "0x64"}
+	if bounds.L1Gas != want {
+		t.Errorf("L1Gas = %+v, want %+v", bounds.L1Gas, want)
+	}
+}
@@ -0,0 +1,235 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/account"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+)
+
+// simulationFlags skip the account __validate__ call, because plan-time
+// transactions aren't signed yet: the signature is only made at apply time,
+// over the final nonce and fee, so validation would reject every simulation.
+// Signature and account problems surface when the transaction is submitted.
+// The fee charge is skipped because the fee is what the simulation
+// estimates: the transactions carry zero fee and resource bounds unless
+// they're set in the configuration, which charging would refuse.
+var simulationFlags = []rpc.SimulationFlag{rpc.SKIP_VALIDATE, rpc.SKIP_FEE_CHARGE}
+
+// SimulationRevertError is returned when the simulated transaction reverts.
+type SimulationRevertError struct {
+	Reason string
+	// Invocation is the trace of the reverted __execute__ call, nil when the
+	// node reports the revert reason only, as RPC 0.7 nodes do for reverted
+	// executions.
+	Invocation *rpc.FnInvocation
+}
+
+func (e *SimulationRevertError) Error() string {
+	return decodeRevertReason(e.Reason)
+}
+
+// CallStack returns the addresses of the contracts the revert went through,
+// starting with the account. Execution stops at the failing call, so it's
+// the last nested call of every invocation. The stack is empty without an
+// invocation trace.
+func (e *SimulationRevertError) CallStack() []string {
+	stack := []string{}
+	for invocation := e.Invocation; invocation != nil; {
+		if invocation.ContractAddress != nil {
+			stack = append(stack, invocation.ContractAddress.String())
+		}
+		if len(invocation.NestedCalls) == 0 {
+			break
+		}
+		invocation = &invocation.NestedCalls[len(invocation.NestedCalls)-1]
+	}
+	return stack
+}
+
+// Detail formats the decoded revert reason with the failing call stack for
+// diagnostics.
+func (e *SimulationRevertError) Detail() string {
+	detail := fmt.Sprintf("Revert reason: %s", e.Error())
+
+	stack := e.CallStack()
+	if len(stack) > 0 {
+		detail += "\n\nCall stack:"
+		for i, address := range stack {
+			detail += fmt.Sprintf("\n  %d. %s", i+1, address)
+		}
+	}
+	return detail
+}
+
+// executionError returns the execution error of a transaction execution
+// RPC error, empty string for other errors.
+func executionError(err error) string {
+	var rpcErr *rpc.RPCError
+	if !errors.As(err, &rpcErr) || rpcErr.Code != rpc.ErrTxnExec.Code {
+		return ""
+	}
+	if data, ok := rpcErr.Data.(map[string]interface{}); ok {
+		if executionError, ok := data["execution_error"].(string); ok {
+			return executionError
+		}
+	}
+	return fmt.Sprint(rpcErr.Data)
+}
+
+// isAlreadyDeclaredError tells whether the node refused a declare
+// transaction because the class is declared already.
+func isAlreadyDeclaredError(err error) bool {
+	var rpcErr *rpc.RPCError
+	if errors.As(err, &rpcErr) && rpcErr.Code == rpc.ErrClassAlreadyDeclared.Code {
+		return true
+	}
+	var revertErr *SimulationRevertError
+	if errors.As(err, &revertErr) {
+		return strings.Contains(revertErr.Reason, "is already declared")
+	}
+	return strings.Contains(executionError(err), "is already declared")
+}
+
+// isClassDeclared tells whether the class is declared on top of the pending
+// block.
+func isClassDeclared(ctx context.Context, client rpc.RpcProvider, classHash *felt.Felt) (bool, error) {
+	_, err := client.Class(ctx, rpc.WithBlockTag("pending"), classHash)
+	var rpcErr *rpc.RPCError
+	if errors.As(err, &rpcErr) && rpcErr.Code == rpc.ErrClassHashNotFound.Code {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// SimulateTransaction simulates the transaction on top of the pending block.
+// A reverted execution is returned as SimulationRevertError.
+func SimulateTransaction(ctx context.Context, client rpc.RpcProvider, tx rpc.BroadcastTxn) (*rpc.FeeEstimation, error) {
+	simulated, err := client.SimulateTransactions(ctx, rpc.WithBlockTag("pending"), []rpc.BroadcastTxn{tx}, simulationFlags)
+	if err != nil {
+		if reason := executionError(err); reason != "" {
+			return nil, &SimulationRevertError{Reason: reason}
+		}
+		return nil, err
+	}
+	if len(simulated) == 0 {
+		return nil, fmt.Errorf("node returned empty simulation")
+	}
+
+	if trace, ok := simulated[0].TxnTrace.(rpc.InvokeTxnTrace); ok && trace.ExecuteInvocation.RevertReason != "" {
+		return nil, &SimulationRevertError{
+			Reason:     trace.ExecuteInvocation.RevertReason,
+			Invocation: trace.ExecuteInvocation.FnInvocation,
+		}
+	}
+
+	return &simulated[0].FeeEstimation, nil
+}
+
+// BuildDeclareTransaction builds an unsigned declare transaction for
+// simulation. Fees are zero unless set in settings.
+func BuildDeclareTransaction(
+	ctx context.Context,
+	a *account.Account,
+	class *rpc.ContractClass,
+	classHash *felt.Felt,
+	compiledClassHash *felt.Felt,
+	settings *TxSettings,
+) (rpc.BroadcastTxn, error) {
+	nonce, err := accountNonce(ctx, a, settings)
+	if err != nil {
+		return nil, err
+	}
+
+	switch settings.Version {
+	case rpc.TransactionV2:
+		return rpc.BroadcastDeclareTxnV2{
+			Type:              rpc.TransactionType_Declare,
+			Version:           rpc.TransactionV2,
+			SenderAddress:     a.AccountAddress,
+			CompiledClassHash: compiledClassHash,
+			Nonce:             nonce,
+			MaxFee:            utils.Uint64ToFelt(0),
+			Signature:         []*felt.Felt{},
+			ContractClass:     *class,
+		}, nil
+	case rpc.TransactionV3:
+		resourceBounds := zeroResourceBounds()
+		if settings.ResourceBounds != nil {
+			resourceBounds = *settings.ResourceBounds
+		}
+		return newBroadcastDeclareTxnV3(rpc.DeclareTxnV3{
+			SenderAddress:         a.AccountAddress,
+			Type:                  rpc.TransactionType_Declare,
+			Version:               rpc.TransactionV3,
+			ClassHash:             classHash,
+			CompiledClassHash:     compiledClassHash,
+			Nonce:                 nonce,
+			Signature:             []*felt.Felt{},
+			ResourceBounds:        resourceBounds,
+			Tip:                   settings.Tip,
+			PayMasterData:         settings.PaymasterData,
+			AccountDeploymentData: []*felt.Felt{},
+			NonceDataMode:         settings.NonceDAMode,
+			FeeMode:               settings.FeeDAMode,
+		}, class), nil
+	}
+	return nil, fmt.Errorf("unsupported declare transaction version %s", settings.Version)
+}
+
+// BuildInvokeTransaction builds an unsigned invoke transaction for
+// simulation. Fees are zero unless set in settings.
+func BuildInvokeTransaction(
+	ctx context.Context,
+	a *account.Account,
+	calls []rpc.FunctionCall,
+	settings *TxSettings,
+) (rpc.BroadcastTxn, error) {
+	nonce, err := accountNonce(ctx, a, settings)
+	if err != nil {
+		return nil, err
+	}
+
+	calldata := account.FmtCallDataCairo2(calls)
+
+	switch settings.Version {
+	case rpc.TransactionV2:
+		return rpc.BroadcastInvokev1Txn{InvokeTxnV1: rpc.InvokeTxnV1{
+			SenderAddress: a.AccountAddress,
+			Type:          rpc.TransactionType_Invoke,
+			Version:       rpc.TransactionV1,
+			Calldata:      calldata,
+			Nonce:         nonce,
+			MaxFee:        utils.Uint64ToFelt(0),
+			Signature:     []*felt.Felt{},
+		}}, nil
+	case rpc.TransactionV3:
+		resourceBounds := zeroResourceBounds()
+		if settings.ResourceBounds != nil {
+			resourceBounds = *settings.ResourceBounds
+		}
+		return rpc.BroadcastInvokev3Txn{InvokeTxnV3: rpc.InvokeTxnV3{
+			SenderAddress:         a.AccountAddress,
+			Type:                  rpc.TransactionType_Invoke,
+			Version:               rpc.TransactionV3,
+			Calldata:              calldata,
+			Nonce:                 nonce,
+			Signature:             []*felt.Felt{},
+			ResourceBounds:        resourceBounds,
+			Tip:                   settings.Tip,
+			PayMasterData:         settings.PaymasterData,
+			AccountDeploymentData: []*felt.Felt{},
+			NonceDataMode:         settings.NonceDAMode,
+			FeeMode:               settings.FeeDAMode,
+		}}, nil
+	}
+	return nil, fmt.Errorf("unsupported invoke transaction version %s", settings.Version)
+}
@@ -0,0 +1,136 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+)
+
+func TestSimulateTransactionRevert(t *testing.T) {
+	invocation := func(address uint64, nested ...rpc.FnInvocation) rpc.FnInvocation {
+		return rpc.FnInvocation{
+			FunctionCall: rpc.FunctionCall{ContractAddress: new(felt.Felt).SetUint64(address)},
+			NestedCalls:  nested,
+		}
+	}
+	simulated := func(trace rpc.TxnTrace) func([]rpc.BroadcastTxn) ([]rpc.SimulatedTransaction, error) {
+		return func([]rpc.BroadcastTxn) ([]rpc.SimulatedTransaction, error) {
+			return []rpc.SimulatedTransaction{{TxnTrace: trace}}, nil
+		}
+	}
+
+	// Reverted executions of RPC 0.7 nodes carry the revert reason only
+	var reasonOnly rpc.InvokeTxnTrace
+	if err := json.Unmarshal([]byte(`{
+		"type": "INVOKE",
+		"execute_invocation": {"revert_reason": "Error in the called contract (0xa)"}
+	}`), &reasonOnly); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		simulate  func([]rpc.BroadcastTxn) ([]rpc.SimulatedTransaction, error)
+		wantStack []string
+		wantFee   bool
+	}{
+		{
+			name: "succeeded",
+			simulate: simulated(rpc.InvokeTxnTrace{ExecuteInvocation: rpc.ExecInvocation{
+				FnInvocation: &rpc.FnInvocation{},
+			}}),
+			wantFee: true,
+		},
+		{
+			name: "reverted with invocation trace",
+			simulate: simulated(rpc.InvokeTxnTrace{ExecuteInvocation: rpc.ExecInvocation{
+				FnInvocation: func() *rpc.FnInvocation {
+					root := invocation(0xa,
+						invocation(0xb),
+						invocation(0xc, invocation(0xd), invocation(0xe)),
+					)
+					return &root
+				}(),
+				RevertReason: "0x4661696c6564 ('Failed')",
+			}}),
+			wantStack: []string{"0xa", "0xc", "0xe"},
+		},
+		{
+			name:      "reverted without invocation trace",
+			simulate:  simulated(reasonOnly),
+			wantStack: []string{},
+		},
+		{
+			name: "execution error",
+			simulate: func([]rpc.BroadcastTxn) ([]rpc.SimulatedTransaction, error) {
+				return nil, &rpc.RPCError{
+					Code:    rpc.ErrTxnExec.Code,
+					Message: rpc.ErrTxnExec.Message,
+					Data:    map[string]interface{}{"execution_error": "Error in the called contract (0xa)"},
+				}
+			},
+			wantStack: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &fakeRpcProvider{simulate: tt.simulate}
+
+			fee, err := SimulateTransaction(context.Background(), client, rpc.BroadcastInvokev3Txn{})
+			if tt.wantFee {
+				if err != nil || fee == nil {
+					t.Fatalf("SimulateTransaction() = %v, %v, want fee", fee, err)
+				}
+				return
+			}
+
+			var revertErr *SimulationRevertError
+			if !errors.As(err, &revertErr) {
+				t.Fatalf("SimulateTransaction() error = %v, want SimulationRevertError", err)
+			}
+			if got := revertErr.CallStack(); !reflect.DeepEqual(got, tt.wantStack) {
+				t.Errorf("CallStack() = %v, want %v", got, tt.wantStack)
+			}
+		})
+	}
+}
+
+func TestIsClassDeclared(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		want    bool
+		wantErr bool
+	}{
+		{name: "declared", want: true},
+		{name: "declared in the same apply", err: rpc.ErrClassHashNotFound, want: false},
+		{name: "node error", err: errors.New("connection refused"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &fakeRpcProvider{
+				class: func(*felt.Felt) (rpc.ClassOutput, error) {
+					if tt.err != nil {
+						return nil, tt.err
+					}
+					return &rpc.ContractClass{}, nil
+				},
+			}
+
+			got, err := isClassDeclared(context.Background(), client, new(felt.Felt).SetUint64(0xabc))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("isClassDeclared() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("isClassDeclared() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/account"
+	"github.com/NethermindEth/starknet.go/rpc"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	// maxSubmitAttempts bounds how many times a transaction is signed and
+	// sent when the node rejects its nonce or fee.
+	maxSubmitAttempts = 3
+	// feeRetryMarginPercent is added to the fee margin after every
+	// insufficient fee error.
+	feeRetryMarginPercent = 50
+)
+
+type submitErrorKind int
+
+const (
+	submitErrorOther submitErrorKind = iota
+	submitErrorNonce
+	submitErrorFee
+)
+
+func (k submitErrorKind) String() string {
+	switch k {
+	case submitErrorNonce:
+		return "invalid nonce"
+	case submitErrorFee:
+		return "insufficient fee"
+	}
+	return "other"
+}
+
+// classifySubmitError tells which submission errors can be recovered from by
+// signing the transaction again. Spec 0.7 reports both a too low max fee and
+// too low resource bounds with the insufficient max fee error.
+func classifySubmitError(err error) submitErrorKind {
+	rpcErr, ok := err.(*rpc.RPCError)
+	if !ok {
+		return submitErrorOther
+	}
+	switch rpcErr.Code {
+	case rpc.ErrInvalidTransactionNonce.Code:
+		return submitErrorNonce
+	case rpc.ErrInsufficientMaxFee.Code:
+		return submitErrorFee
+	}
+	return submitErrorOther
+}
+
+// SubmittedTransaction is a signed transaction accepted by the node.
+type SubmittedTransaction struct {
+	TransactionHash *felt.Felt
+	Nonce           *felt.Felt
+	Transaction     rpc.BroadcastTxn
+
+	// settings and sign are kept to replace a stuck transaction.
+	settings TxSettings
+	sign     SignTransactionFunc
+	// nonces is resynced when the transaction turns out to be dropped.
+	nonces  *NonceManager
+	account *felt.Felt
+	// replaced holds hashes of the transactions replaced with this one.
+	replaced []*felt.Felt
+}
+
+// resyncNonce makes the next transaction of the account read its nonce from
+// the node, used when this transaction was dropped or may have been.
+func (s *SubmittedTransaction) resyncNonce(ctx context.Context) {
+	if s.nonces == nil {
+		return
+	}
+	// Waiting may have stopped because ctx is done
+	ctx = context.WithoutCancel(ctx)
+	if err := s.nonces.Resync(ctx, s.account); err != nil {
+		tflog.Warn(ctx, "can't resync account nonce", map[string]interface{}{
+			"account": s.account.String(),
+			"error":   err.Error(),
+		})
+	}
+}
+
+// SignTransactionFunc builds and signs a transaction with the nonce and fee
+// margin from settings.
+type SignTransactionFunc func(ctx context.Context, settings *TxSettings) (rpc.BroadcastTxn, error)
+
+// SubmitTransaction signs the transaction with a nonce reserved from nonces
+// and sends it. When the node rejects the nonce, the nonce is read from the
+// node again, when it rejects the fee, the fee is estimated again with a
+// higher margin. A nonce rejected by the node is never used again, and the
+// nonce of a sent transaction is never handed out again.
+func SubmitTransaction(
+	ctx context.Context,
+	nonces *NonceManager,
+	a *account.Account,
+	settings *TxSettings,
+	sign SignTransactionFunc,
+) (*SubmittedTransaction, error) {
+	rejectedNonces := map[string]bool{}
+	extraFeeMargin := settings.ExtraFeeMarginPercent
+
+	for attempt := 1; ; attempt++ {
+		// The account stays locked until the transaction is sent, so
+		// parallel resources signing with the same account get
+		// sequential nonces.
+		lease, err := nonces.Acquire(ctx, a.AccountAddress)
+		if err != nil {
+			return nil, fmt.Errorf("can't get account nonce: %w", err)
+		}
+		if rejectedNonces[lease.Nonce.String()] {
+			lease.Release()
+			return nil, fmt.Errorf("node rejected nonce %s and still reports it as the account nonce", lease.Nonce)
+		}
+
+		attemptSettings := *settings
+		attemptSettings.Nonce = lease.Nonce
+		attemptSettings.ExtraFeeMarginPercent = extraFeeMargin
+
+		tx, err := sign(ctx, &attemptSettings)
+		if err != nil {
+			lease.Release()
+			return nil, fmt.Errorf("can't sign and estimate transaction: %w", err)
+		}
+
+		response, err := a.SendTransaction(ctx, tx)
+		if err == nil {
+			lease.Commit()
+			return &SubmittedTransaction{
+				TransactionHash: response.TransactionHash,
+				Nonce:           lease.Nonce,
+				Transaction:     tx,
+				settings:        attemptSettings,
+				sign:            sign,
+				nonces:          nonces,
+				account:         a.AccountAddress,
+			}, nil
+		}
+
+		// Whether the node kept the transaction isn't known after a failed
+		// send, the nonce is read from the node again.
+		kind := classifySubmitError(err)
+		if kind == submitErrorNonce {
+			rejectedNonces[lease.Nonce.String()] = true
+		}
+		lease.Resync()
+
+		// Fixed resource bounds can't be raised by estimating again.
+		recoverable := kind == submitErrorNonce ||
+			(kind == submitErrorFee && settings.ResourceBounds == nil)
+		if !recoverable || attempt >= maxSubmitAttempts {
+			return nil, fmt.Errorf("can't send transaction: %w", err)
+		}
+
+		if kind == submitErrorFee {
+			extraFeeMargin += feeRetryMarginPercent
+		}
+
+		tflog.Warn(ctx, "transaction rejected, signing it again", map[string]interface{}{
+			"account":                  a.AccountAddress.String(),
+			"attempt":                  attempt,
+			"reason":                   kind.String(),
+			"nonce":                    lease.Nonce.String(),
+			"error":                    err.Error(),
+			"extra_fee_margin_percent": extraFeeMargin,
+		})
+	}
+}
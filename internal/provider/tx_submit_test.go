@@ -0,0 +1,198 @@
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+)
+
+// fakeMempool accepts invoke transactions carrying its account nonce, unless
+// failures are scripted for the next sends.
+type fakeMempool struct {
+	nonce uint64
+	// failures are returned by the next sends in order, nil sends normally.
+	// A nonce error also takes the nonce, as a transaction sent from
+	// elsewhere would.
+	failures []error
+	// signed holds the nonce and extra fee margin of every signed
+	// transaction, accepted the nonces the node accepted.
+	signed   [][2]uint64
+	accepted []uint64
+}
+
+func (n *fakeMempool) client() *fakeRpcProvider {
+	return &fakeRpcProvider{
+		nonce: func(*felt.Felt) (*felt.Felt, error) {
+			return new(felt.Felt).SetUint64(n.nonce), nil
+		},
+		addInvoke: func(tx rpc.BroadcastInvokeTxnType) (*rpc.AddInvokeTransactionResponse, error) {
+			nonce := tx.(rpc.BroadcastInvokev3Txn).Nonce.Uint64()
+
+			if len(n.failures) > 0 {
+				failure := n.failures[0]
+				n.failures = n.failures[1:]
+				if failure == rpc.ErrInvalidTransactionNonce {
+					n.nonce++
+				}
+				if failure != nil {
+					return nil, failure
+				}
+			}
+			if nonce != n.nonce {
+				return nil, rpc.ErrInvalidTransactionNonce
+			}
+
+			n.nonce++
+			n.accepted = append(n.accepted, nonce)
+			return &rpc.AddInvokeTransactionResponse{TransactionHash: new(felt.Felt).SetUint64(0x1000 + nonce)}, nil
+		},
+	}
+}
+
+func (n *fakeMempool) sign(ctx context.Context, settings *TxSettings) (rpc.BroadcastTxn, error) {
+	n.signed = append(n.signed, [2]uint64{settings.Nonce.Uint64(), uint64(settings.ExtraFeeMarginPercent)})
+	return rpc.BroadcastInvokev3Txn{InvokeTxnV3: rpc.InvokeTxnV3{
+		Type:    rpc.TransactionType_Invoke,
+		Version: rpc.TransactionV3,
+		Nonce:   settings.Nonce,
+	}}, nil
+}
+
+func TestSubmitTransaction(t *testing.T) {
+	tests := []struct {
+		name     string
+		failures []error
+		// fixedBounds sets the resource bounds, so fees aren't estimated
+		fixedBounds bool
+		// wantSigned is the nonce and extra fee margin of every signed
+		// transaction of the first submission.
+		wantSigned [][2]uint64
+		wantErr    string
+	}{
+		{
+			name:       "sent",
+			wantSigned: [][2]uint64{{5, 0}},
+		},
+		{
+			name:       "nonce taken, signed with the next one",
+			failures:   []error{rpc.ErrInvalidTransactionNonce},
+			wantSigned: [][2]uint64{{5, 0}, {6, 0}},
+		},
+		{
+			name:       "insufficient fee, estimated again with a higher margin",
+			failures:   []error{rpc.ErrInsufficientMaxFee},
+			wantSigned: [][2]uint64{{5, 0}, {5, feeRetryMarginPercent}},
+		},
+		{
+			name:        "insufficient fee with fixed resource bounds",
+			failures:    []error{rpc.ErrInsufficientMaxFee},
+			fixedBounds: true,
+			wantSigned:  [][2]uint64{{5, 0}},
+			wantErr:     "can't send transaction",
+		},
+		{
+			name:       "attempts exhausted",
+			failures:   []error{rpc.ErrInsufficientMaxFee, rpc.ErrInvalidTransactionNonce, rpc.ErrInsufficientMaxFee},
+			wantSigned: [][2]uint64{{5, 0}, {5, feeRetryMarginPercent}, {6, feeRetryMarginPercent}},
+			wantErr:    "can't send transaction",
+		},
+		{
+			name:       "other error",
+			failures:   []error{rpc.ErrValidationFailure},
+			wantSigned: [][2]uint64{{5, 0}},
+			wantErr:    "can't send transaction",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			node := &fakeMempool{nonce: 5, failures: tt.failures}
+			client := node.client()
+			a := newFakeAccount(t, client, new(felt.Felt).SetUint64(0x1234))
+			nonces := NewNonceManager(client)
+
+			settings := &TxSettings{Version: rpc.TransactionV3, Tip: "0x0"}
+			if tt.fixedBounds {
+				bounds := zeroResourceBounds()
+				settings.ResourceBounds = &bounds
+			}
+
+			submitted, err := SubmitTransaction(ctx, nonces, a, settings, node.sign)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("SubmitTransaction() error = %v, want %q", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("SubmitTransaction() error = %v", err)
+			} else if !submitted.Nonce.Equal(new(felt.Felt).SetUint64(node.accepted[0])) {
+				t.Errorf("SubmitTransaction() nonce = %s, want %d", submitted.Nonce, node.accepted[0])
+			}
+
+			if len(node.signed) != len(tt.wantSigned) {
+				t.Fatalf("SubmitTransaction() signed %v, want %v", node.signed, tt.wantSigned)
+			}
+			for i, want := range tt.wantSigned {
+				if node.signed[i] != want {
+					t.Errorf("SubmitTransaction() signed %v, want %v", node.signed, tt.wantSigned)
+					break
+				}
+			}
+
+			// The next transaction of the account gets a fresh nonce
+			node.failures = nil
+			if _, err := SubmitTransaction(ctx, nonces, a, settings, node.sign); err != nil {
+				t.Fatalf("next SubmitTransaction() error = %v", err)
+			}
+			seen := map[uint64]bool{}
+			for _, nonce := range node.accepted {
+				if seen[nonce] {
+					t.Fatalf("node accepted nonces %v, want no nonce twice", node.accepted)
+				}
+				seen[nonce] = true
+			}
+		})
+	}
+}
+
+func TestSubmitTransactionRejectedNonceReported(t *testing.T) {
+	node := &fakeMempool{nonce: 5}
+	client := node.client()
+	// The node keeps rejecting the nonce it reports, e.g. a lagging
+	// endpoint behind failover
+	client.addInvoke = func(tx rpc.BroadcastInvokeTxnType) (*rpc.AddInvokeTransactionResponse, error) {
+		return nil, rpc.ErrInvalidTransactionNonce
+	}
+	a := newFakeAccount(t, client, new(felt.Felt).SetUint64(0x1234))
+
+	_, err := SubmitTransaction(context.Background(), NewNonceManager(client), a, &TxSettings{Version: rpc.TransactionV3}, node.sign)
+	if err == nil || !strings.Contains(err.Error(), "still reports it") {
+		t.Fatalf("SubmitTransaction() error = %v, want rejected nonce error", err)
+	}
+	if len(node.signed) != 1 {
+		t.Errorf("SubmitTransaction() signed %v, want nonce 5 signed once", node.signed)
+	}
+}
+
+func TestClassifySubmitError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want submitErrorKind
+	}{
+		{err: rpc.ErrInvalidTransactionNonce, want: submitErrorNonce},
+		{err: rpc.ErrInsufficientMaxFee, want: submitErrorFee},
+		{err: rpc.ErrInsufficientAccountBalance, want: submitErrorOther},
+		{err: context.DeadlineExceeded, want: submitErrorOther},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.err.Error(), func(t *testing.T) {
+			if got := classifySubmitError(tt.err); got != tt.want {
+				t.Errorf("classifySubmitError() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
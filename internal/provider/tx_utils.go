@@ -2,6 +2,8 @@ package provider
 
 import (
 	"context"
+	"fmt"
+	"math/big"
 	"strconv"
 
 	"github.com/NethermindEth/juno/core/felt"
@@ -10,17 +12,24 @@ import (
 	"github.com/NethermindEth/starknet.go/utils"
 )
 
+// accountNonce returns the nonce reserved for the transaction in settings,
+// or the account nonce at the latest block when none is reserved.
+func accountNonce(ctx context.Context, a *account.Account, settings *TxSettings) (*felt.Felt, error) {
+	if settings.Nonce != nil {
+		return settings.Nonce, nil
+	}
+	return a.Nonce(ctx, rpc.BlockID{Tag: "latest"}, a.AccountAddress)
+}
+
 func GetFeeForDeclareV2(
+	ctx context.Context,
 	a *account.Account,
 	class *rpc.ContractClass,
 	classHash *felt.Felt,
 	compiledClassHash *felt.Felt,
+	settings *TxSettings,
 ) (*uint64, error) {
-	nonce, err := a.Nonce(
-		context.Background(),
-		rpc.BlockID{Tag: "latest"},
-		a.AccountAddress,
-	)
+	nonce, err := accountNonce(ctx, a, settings)
 	if err != nil {
 		return nil, err
 	}
@@ -35,7 +44,7 @@ func GetFeeForDeclareV2(
 		MaxFee:            utils.Uint64ToFelt(0),
 	}
 
-	err = a.SignDeclareTransaction(context.Background(), &tx)
+	err = a.SignDeclareTransaction(ctx, &tx)
 	if err != nil {
 		return nil, err
 	}
@@ -52,21 +61,19 @@ func GetFeeForDeclareV2(
 	}
 
 	estimation, err := a.EstimateFee(
-		context.Background(),
+		ctx,
 		[]rpc.BroadcastTxn{broadcastTxForEstimation},
 		[]rpc.SimulationFlag{},
 		rpc.WithBlockTag("latest"),
 	)
-	if err, ok := err.(*rpc.RPCError); ok {
+	if err != nil {
 		return nil, err
 	}
-
-	var fee uint64 = 1
 	if len(estimation) == 0 {
-		return &fee, nil
+		return nil, fmt.Errorf("node returned empty fee estimation")
 	}
 
-	fee, err = strconv.ParseUint(estimation[0].OverallFee.String(), 0, 64)
+	fee, err := strconv.ParseUint(estimation[0].OverallFee.String(), 0, 64)
 	if err != nil {
 		return nil, err
 	}
@@ -74,23 +81,290 @@ func GetFeeForDeclareV2(
 	return &fee, nil
 }
 
-func SignAndEstimateDeclareTransaction(
+// feeMarginPercent is applied on top of estimated V3 resource bounds to
+// absorb gas price changes between estimation and inclusion.
+const feeMarginPercent = 150
+
+func withFeeMargin(value *big.Int, percent int64) *big.Int {
+	result := new(big.Int).Mul(value, big.NewInt(percent))
+	return result.Div(result, big.NewInt(100))
+}
+
+// maxFeeFromEstimation converts an estimated V1/V2 fee into the max fee,
+// adding the extra margin requested in settings.
+func maxFeeFromEstimation(fee uint64, settings *TxSettings) *felt.Felt {
+	maxFee := withFeeMargin(new(big.Int).SetUint64(fee), 100+settings.ExtraFeeMarginPercent)
+	return new(felt.Felt).SetBigInt(maxFee)
+}
+
+// maxU128 is the largest value of the u128 fields of a transaction.
+var maxU128 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1))
+
+// newResourceBounds formats a resource bound, checking the amount fits u64
+// and the price fits u128.
+func newResourceBounds(maxAmount *big.Int, maxPricePerUnit *big.Int) (rpc.ResourceBounds, error) {
+	if maxAmount.Sign() < 0 || !maxAmount.IsUint64() {
+		return rpc.ResourceBounds{}, fmt.Errorf("max amount %s doesn't fit u64", maxAmount)
+	}
+	if maxPricePerUnit.Sign() < 0 || maxPricePerUnit.Cmp(maxU128) > 0 {
+		return rpc.ResourceBounds{}, fmt.Errorf("max price per unit %s doesn't fit u128", maxPricePerUnit)
+	}
+	return rpc.ResourceBounds{
+		MaxAmount:       rpc.U64(fmt.Sprintf("%#x", maxAmount.Uint64())),
+		MaxPricePerUnit: rpc.U128(fmt.Sprintf("%#x", maxPricePerUnit)),
+	}, nil
+}
+
+// withDataGas returns the L1 gas amount that also pays for the L1 data gas.
+// Spec 0.7 transactions have no L1 data gas bound, the sequencer charges
+// data gas against the L1 gas bound, so data gas is converted to L1 gas
+// units at the L1 gas price, rounding up.
+func withDataGas(l1GasAmount, l1GasPrice, dataGasAmount, dataGasPrice *big.Int) (*big.Int, error) {
+	dataFee := new(big.Int).Mul(dataGasAmount, dataGasPrice)
+	if dataFee.Sign() == 0 {
+		return new(big.Int).Set(l1GasAmount), nil
+	}
+	if l1GasPrice.Sign() == 0 {
+		return nil, fmt.Errorf("L1 data gas can't be paid with zero L1 gas price")
+	}
+
+	dataGasInL1Gas := new(big.Int).Add(dataFee, new(big.Int).Sub(l1GasPrice, big.NewInt(1)))
+	dataGasInL1Gas.Div(dataGasInL1Gas, l1GasPrice)
+	return dataGasInL1Gas.Add(dataGasInL1Gas, l1GasAmount), nil
+}
+
+// estimationValue returns a fee estimation field, zero when the node left it
+// out.
+func estimationValue(value *felt.Felt) *big.Int {
+	if value == nil {
+		return new(big.Int)
+	}
+	return value.BigInt(new(big.Int))
+}
+
+// resourceBoundsFromEstimation converts a fee estimation into V3 resource
+// bounds. L1 gas and L1 data gas are bounded by their own consumed amount
+// and price, with the margin added to both. Spec 0.7 estimates report no L2
+// gas, it isn't charged yet, so the L2 gas bound stays zero.
+func resourceBoundsFromEstimation(estimation []rpc.FeeEstimation, settings *TxSettings) (*rpc.ResourceBoundsMapping, error) {
+	if len(estimation) == 0 {
+		return nil, fmt.Errorf("node returned empty fee estimation")
+	}
+	margin := feeMarginPercent + settings.ExtraFeeMarginPercent
+
+	gasPrice := estimationValue(estimation[0].GasPrice)
+	if gasPrice.Sign() == 0 {
+		return nil, fmt.Errorf("node returned zero gas price")
+	}
+	gasPrice = withFeeMargin(gasPrice, margin)
+	gasAmount := withFeeMargin(estimationValue(estimation[0].GasConsumed), margin)
+
+	dataGasPrice := withFeeMargin(estimationValue(estimation[0].DataGasPrice), margin)
+	dataGasAmount := withFeeMargin(estimationValue(estimation[0].DataGasConsumed), margin)
+
+	gasAmount, err := withDataGas(gasAmount, gasPrice, dataGasAmount, dataGasPrice)
+	if err != nil {
+		return nil, err
+	}
+	// Rounding of the margin must not bring the bound below the estimate
+	gasAmount.Add(gasAmount, big.NewInt(1))
+
+	l1Gas, err := newResourceBounds(gasAmount, gasPrice)
+	if err != nil {
+		return nil, fmt.Errorf("estimated L1 gas: %w", err)
+	}
+
+	return &rpc.ResourceBoundsMapping{
+		L1Gas: l1Gas,
+		L2Gas: rpc.ResourceBounds{
+			MaxAmount:       rpc.U64("0x0"),
+			MaxPricePerUnit: rpc.U128("0x0"),
+		},
+	}, nil
+}
+
+func signDeclareTransactionV3(ctx context.Context, a *account.Account, tx *rpc.DeclareTxnV3) error {
+	txHash, err := a.TransactionHashDeclare(*tx)
+	if err != nil {
+		return err
+	}
+
+	signature, err := a.Sign(ctx, txHash)
+	if err != nil {
+		return err
+	}
+
+	tx.Signature = signature
+	return nil
+}
+
+func signInvokeTransactionV3(ctx context.Context, a *account.Account, tx *rpc.InvokeTxnV3) error {
+	txHash, err := a.TransactionHashInvoke(*tx)
+	if err != nil {
+		return err
+	}
+
+	signature, err := a.Sign(ctx, txHash)
+	if err != nil {
+		return err
+	}
+
+	tx.Signature = signature
+	return nil
+}
+
+func newBroadcastDeclareTxnV3(tx rpc.DeclareTxnV3, class *rpc.ContractClass) rpc.BroadcastDeclareTxnV3 {
+	return rpc.BroadcastDeclareTxnV3{
+		Type:                  tx.Type,
+		SenderAddress:         tx.SenderAddress,
+		CompiledClassHash:     tx.CompiledClassHash,
+		Version:               tx.Version,
+		Signature:             tx.Signature,
+		Nonce:                 tx.Nonce,
+		ContractClass:         class,
+		ResourceBounds:        tx.ResourceBounds,
+		Tip:                   tx.Tip,
+		PayMasterData:         tx.PayMasterData,
+		AccountDeploymentData: tx.AccountDeploymentData,
+		NonceDataMode:         tx.NonceDataMode,
+		FeeMode:               tx.FeeMode,
+	}
+}
+
+func GetResourceBoundsForDeclareV3(
+	ctx context.Context,
 	a *account.Account,
 	class *rpc.ContractClass,
 	classHash *felt.Felt,
 	compiledClassHash *felt.Felt,
-) (*rpc.BroadcastDeclareTxnV2, error) {
-	fee, err := GetFeeForDeclareV2(a, class, classHash, compiledClassHash)
+	settings *TxSettings,
+) (*rpc.ResourceBoundsMapping, error) {
+	nonce, err := accountNonce(ctx, a, settings)
 	if err != nil {
 		return nil, err
 	}
 
-	nonce, err := a.Nonce(
-		context.Background(),
-		rpc.BlockID{Tag: "latest"},
-		a.AccountAddress,
+	tx := rpc.DeclareTxnV3{
+		SenderAddress:         a.AccountAddress,
+		Type:                  rpc.TransactionType_Declare,
+		Version:               rpc.TransactionV3,
+		ClassHash:             classHash,
+		CompiledClassHash:     compiledClassHash,
+		Nonce:                 nonce,
+		ResourceBounds:        zeroResourceBounds(),
+		Tip:                   settings.Tip,
+		PayMasterData:         settings.PaymasterData,
+		AccountDeploymentData: []*felt.Felt{},
+		NonceDataMode:         settings.NonceDAMode,
+		FeeMode:               settings.FeeDAMode,
+	}
+
+	err = signDeclareTransactionV3(ctx, a, &tx)
+	if err != nil {
+		return nil, err
+	}
+
+	estimation, err := a.EstimateFee(
+		ctx,
+		[]rpc.BroadcastTxn{newBroadcastDeclareTxnV3(tx, class)},
+		[]rpc.SimulationFlag{},
+		rpc.WithBlockTag("latest"),
 	)
+	if err != nil {
+		return nil, err
+	}
+
+	return resourceBoundsFromEstimation(estimation, settings)
+}
 
+// zeroResourceBounds is used while estimating, the node doesn't check bounds
+// for fee estimation requests.
+func zeroResourceBounds() rpc.ResourceBoundsMapping {
+	return rpc.ResourceBoundsMapping{
+		L1Gas: rpc.ResourceBounds{MaxAmount: rpc.U64("0x0"), MaxPricePerUnit: rpc.U128("0x0")},
+		L2Gas: rpc.ResourceBounds{MaxAmount: rpc.U64("0x0"), MaxPricePerUnit: rpc.U128("0x0")},
+	}
+}
+
+// SignAndEstimateDeclareTransaction builds, estimates and signs a declare
+// transaction of the version selected in settings. V2 transactions pay fees
+// in ETH, V3 transactions pay fees in STRK.
+func SignAndEstimateDeclareTransaction(
+	ctx context.Context,
+	a *account.Account,
+	class *rpc.ContractClass,
+	classHash *felt.Felt,
+	compiledClassHash *felt.Felt,
+	settings *TxSettings,
+) (rpc.BroadcastTxn, error) {
+	switch settings.Version {
+	case rpc.TransactionV2:
+		return signAndEstimateDeclareTransactionV2(ctx, a, class, classHash, compiledClassHash, settings)
+	case rpc.TransactionV3:
+		return signAndEstimateDeclareTransactionV3(ctx, a, class, classHash, compiledClassHash, settings)
+	}
+	return nil, fmt.Errorf("unsupported declare transaction version %s", settings.Version)
+}
+
+func signAndEstimateDeclareTransactionV3(
+	ctx context.Context,
+	a *account.Account,
+	class *rpc.ContractClass,
+	classHash *felt.Felt,
+	compiledClassHash *felt.Felt,
+	settings *TxSettings,
+) (rpc.BroadcastTxn, error) {
+	resourceBounds := settings.ResourceBounds
+	if resourceBounds == nil {
+		var err error
+		resourceBounds, err = GetResourceBoundsForDeclareV3(ctx, a, class, classHash, compiledClassHash, settings)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	nonce, err := accountNonce(ctx, a, settings)
+	if err != nil {
+		return nil, err
+	}
+
+	tx := rpc.DeclareTxnV3{
+		SenderAddress:         a.AccountAddress,
+		Type:                  rpc.TransactionType_Declare,
+		Version:               rpc.TransactionV3,
+		ClassHash:             classHash,
+		CompiledClassHash:     compiledClassHash,
+		Nonce:                 nonce,
+		ResourceBounds:        *resourceBounds,
+		Tip:                   settings.Tip,
+		PayMasterData:         settings.PaymasterData,
+		AccountDeploymentData: []*felt.Felt{},
+		NonceDataMode:         settings.NonceDAMode,
+		FeeMode:               settings.FeeDAMode,
+	}
+
+	err = signDeclareTransactionV3(ctx, a, &tx)
+	if err != nil {
+		return nil, err
+	}
+
+	return newBroadcastDeclareTxnV3(tx, class), nil
+}
+
+func signAndEstimateDeclareTransactionV2(
+	ctx context.Context,
+	a *account.Account,
+	class *rpc.ContractClass,
+	classHash *felt.Felt,
+	compiledClassHash *felt.Felt,
+	settings *TxSettings,
+) (rpc.BroadcastTxn, error) {
+	fee, err := GetFeeForDeclareV2(ctx, a, class, classHash, compiledClassHash, settings)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce, err := accountNonce(ctx, a, settings)
 	if err != nil {
 		return nil, err
 	}
@@ -102,10 +376,10 @@ func SignAndEstimateDeclareTransaction(
 		ClassHash:         classHash,
 		CompiledClassHash: compiledClassHash,
 		Nonce:             nonce,
-		MaxFee:            utils.Uint64ToFelt(*fee),
+		MaxFee:            maxFeeFromEstimation(*fee, settings),
 	}
 
-	err = a.SignDeclareTransaction(context.Background(), &tx)
+	err = a.SignDeclareTransaction(ctx, &tx)
 	if err != nil {
 		return nil, err
 	}
@@ -121,5 +395,189 @@ func SignAndEstimateDeclareTransaction(
 		ContractClass:     *class,
 	}
 
-	return &broadcastTx, nil
+	return broadcastTx, nil
+}
+
+func GetFeeForInvokeV1(
+	ctx context.Context,
+	a *account.Account,
+	calldata []*felt.Felt,
+	settings *TxSettings,
+) (*uint64, error) {
+	nonce, err := accountNonce(ctx, a, settings)
+	if err != nil {
+		return nil, err
+	}
+
+	tx := rpc.InvokeTxnV1{
+		SenderAddress: a.AccountAddress,
+		Type:          rpc.TransactionType_Invoke,
+		Version:       rpc.TransactionV1,
+		Calldata:      calldata,
+		Nonce:         nonce,
+		MaxFee:        utils.Uint64ToFelt(0),
+	}
+
+	err = a.SignInvokeTransaction(ctx, &tx)
+	if err != nil {
+		return nil, err
+	}
+
+	estimation, err := a.EstimateFee(
+		ctx,
+		[]rpc.BroadcastTxn{rpc.BroadcastInvokev1Txn{InvokeTxnV1: tx}},
+		[]rpc.SimulationFlag{},
+		rpc.WithBlockTag("latest"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if len(estimation) == 0 {
+		return nil, fmt.Errorf("node returned empty fee estimation")
+	}
+
+	fee, err := strconv.ParseUint(estimation[0].OverallFee.String(), 0, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	return &fee, nil
+}
+
+// SignAndEstimateInvokeTransaction builds, estimates and signs an invoke
+// transaction executing calls from the account. Version v2 in settings
+// selects the legacy V1 invoke paying fees in ETH.
+func SignAndEstimateInvokeTransaction(
+	ctx context.Context,
+	a *account.Account,
+	calls []rpc.FunctionCall,
+	settings *TxSettings,
+) (rpc.BroadcastTxn, error) {
+	calldata := account.FmtCallDataCairo2(calls)
+
+	switch settings.Version {
+	case rpc.TransactionV2:
+		return signAndEstimateInvokeTransactionV1(ctx, a, calldata, settings)
+	case rpc.TransactionV3:
+		return signAndEstimateInvokeTransactionV3(ctx, a, calldata, settings)
+	}
+	return nil, fmt.Errorf("unsupported invoke transaction version %s", settings.Version)
+}
+
+func GetResourceBoundsForInvokeV3(
+	ctx context.Context,
+	a *account.Account,
+	calldata []*felt.Felt,
+	settings *TxSettings,
+) (*rpc.ResourceBoundsMapping, error) {
+	nonce, err := accountNonce(ctx, a, settings)
+	if err != nil {
+		return nil, err
+	}
+
+	tx := rpc.InvokeTxnV3{
+		SenderAddress:         a.AccountAddress,
+		Type:                  rpc.TransactionType_Invoke,
+		Version:               rpc.TransactionV3,
+		Calldata:              calldata,
+		Nonce:                 nonce,
+		ResourceBounds:        zeroResourceBounds(),
+		Tip:                   settings.Tip,
+		PayMasterData:         settings.PaymasterData,
+		AccountDeploymentData: []*felt.Felt{},
+		NonceDataMode:         settings.NonceDAMode,
+		FeeMode:               settings.FeeDAMode,
+	}
+
+	err = signInvokeTransactionV3(ctx, a, &tx)
+	if err != nil {
+		return nil, err
+	}
+
+	estimation, err := a.EstimateFee(
+		ctx,
+		[]rpc.BroadcastTxn{rpc.BroadcastInvokev3Txn{InvokeTxnV3: tx}},
+		[]rpc.SimulationFlag{},
+		rpc.WithBlockTag("latest"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return resourceBoundsFromEstimation(estimation, settings)
+}
+
+func signAndEstimateInvokeTransactionV3(
+	ctx context.Context,
+	a *account.Account,
+	calldata []*felt.Felt,
+	settings *TxSettings,
+) (rpc.BroadcastTxn, error) {
+	resourceBounds := settings.ResourceBounds
+	if resourceBounds == nil {
+		var err error
+		resourceBounds, err = GetResourceBoundsForInvokeV3(ctx, a, calldata, settings)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	nonce, err := accountNonce(ctx, a, settings)
+	if err != nil {
+		return nil, err
+	}
+
+	tx := rpc.InvokeTxnV3{
+		SenderAddress:         a.AccountAddress,
+		Type:                  rpc.TransactionType_Invoke,
+		Version:               rpc.TransactionV3,
+		Calldata:              calldata,
+		Nonce:                 nonce,
+		ResourceBounds:        *resourceBounds,
+		Tip:                   settings.Tip,
+		PayMasterData:         settings.PaymasterData,
+		AccountDeploymentData: []*felt.Felt{},
+		NonceDataMode:         settings.NonceDAMode,
+		FeeMode:               settings.FeeDAMode,
+	}
+
+	err = signInvokeTransactionV3(ctx, a, &tx)
+	if err != nil {
+		return nil, err
+	}
+
+	return rpc.BroadcastInvokev3Txn{InvokeTxnV3: tx}, nil
+}
+
+func signAndEstimateInvokeTransactionV1(
+	ctx context.Context,
+	a *account.Account,
+	calldata []*felt.Felt,
+	settings *TxSettings,
+) (rpc.BroadcastTxn, error) {
+	fee, err := GetFeeForInvokeV1(ctx, a, calldata, settings)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce, err := accountNonce(ctx, a, settings)
+	if err != nil {
+		return nil, err
+	}
+
+	tx := rpc.InvokeTxnV1{
+		SenderAddress: a.AccountAddress,
+		Type:          rpc.TransactionType_Invoke,
+		Version:       rpc.TransactionV1,
+		Calldata:      calldata,
+		Nonce:         nonce,
+		MaxFee:        maxFeeFromEstimation(*fee, settings),
+	}
+
+	err = a.SignInvokeTransaction(ctx, &tx)
+	if err != nil {
+		return nil, err
+	}
+
+	return rpc.BroadcastInvokev1Txn{InvokeTxnV1: tx}, nil
 }
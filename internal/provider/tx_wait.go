@@ -0,0 +1,180 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	framework_types "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type WaitFor string
+
+const (
+	WaitForNone         WaitFor = "none"
+	WaitForPreConfirmed WaitFor = "pre_confirmed"
+	WaitForAcceptedOnL2 WaitFor = "accepted_on_l2"
+	WaitForAcceptedOnL1 WaitFor = "accepted_on_l1"
+)
+
+const (
+	DefaultWaitFor       = WaitForAcceptedOnL2
+	DefaultPollInterval  = 5 * time.Second
+	DefaultCreateTimeout = 30 * time.Minute
+)
+
+// WaitOptions controls how long a resource waits for its transaction.
+type WaitOptions struct {
+	WaitFor      WaitFor
+	PollInterval time.Duration
+
+	// RebroadcastAfter is how long a transaction may stay RECEIVED or
+	// unknown before it's sent again, zero disables rebroadcasting.
+	RebroadcastAfter time.Duration
+	// ReplaceStuck replaces stuck V3 transactions with ones paying a
+	// higher tip instead of sending the same transaction again.
+	ReplaceStuck bool
+}
+
+// TransactionWaitModel describes the wait attributes shared by the
+// transaction sending resources.
+type TransactionWaitModel struct {
+	WaitFor      framework_types.String `tfsdk:"wait_for"`
+	PollInterval framework_types.String `tfsdk:"poll_interval"`
+}
+
+// transactionWaitAttributes returns the schema of TransactionWaitModel.
+func transactionWaitAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"wait_for": schema.StringAttribute{
+			Optional: true,
+			MarkdownDescription: "Transaction status to wait for: `none`, `pre_confirmed`, `accepted_on_l2` or `accepted_on_l1`. " +
+				"Defaults to the provider setting.",
+		},
+		"poll_interval": schema.StringAttribute{
+			Optional:            true,
+			MarkdownDescription: "Interval between transaction status checks, e.g. `5s`. Defaults to the provider setting.",
+		},
+	}
+}
+
+// withTransactionWait adds the TransactionWaitModel attributes to the
+// resource attributes.
+func withTransactionWait(attributes map[string]schema.Attribute) map[string]schema.Attribute {
+	for name, attribute := range transactionWaitAttributes() {
+		attributes[name] = attribute
+	}
+	return attributes
+}
+
+func parseWaitFor(value string) (WaitFor, error) {
+	switch WaitFor(value) {
+	case WaitForNone, WaitForPreConfirmed, WaitForAcceptedOnL2, WaitForAcceptedOnL1:
+		return WaitFor(value), nil
+	}
+	return "", fmt.Errorf(
+		"unsupported value %q, expected one of %s, %s, %s, %s",
+		value, WaitForNone, WaitForPreConfirmed, WaitForAcceptedOnL2, WaitForAcceptedOnL1,
+	)
+}
+
+func parsePositiveDuration(value string) (time.Duration, error) {
+	interval, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, err
+	}
+	if interval <= 0 {
+		return 0, fmt.Errorf("duration must be positive, got %s", value)
+	}
+	return interval, nil
+}
+
+// WaitOptions converts the model into WaitOptions, falling back to the
+// provider defaults for values the resource doesn't set.
+func (m TransactionWaitModel) WaitOptions(defaults WaitOptions) (*WaitOptions, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	options := defaults
+	if options.WaitFor == "" {
+		options.WaitFor = DefaultWaitFor
+	}
+	if options.PollInterval == 0 {
+		options.PollInterval = DefaultPollInterval
+	}
+
+	if !m.WaitFor.IsNull() {
+		waitFor, err := parseWaitFor(m.WaitFor.ValueString())
+		if err != nil {
+			diags.AddError("Invalid wait_for", err.Error())
+			return nil, diags
+		}
+		options.WaitFor = waitFor
+	}
+
+	if !m.PollInterval.IsNull() {
+		interval, err := parsePositiveDuration(m.PollInterval.ValueString())
+		if err != nil {
+			diags.AddError("Invalid poll_interval", err.Error())
+			return nil, diags
+		}
+		options.PollInterval = interval
+	}
+
+	return &options, diags
+}
+
+func receiptReached(receipt *rpc.TransactionReceiptWithBlockInfo, waitFor WaitFor) bool {
+	switch waitFor {
+	case WaitForPreConfirmed:
+		return true
+	case WaitForAcceptedOnL2:
+		return receipt.FinalityStatus == rpc.TxnFinalityStatusAcceptedOnL2 ||
+			receipt.FinalityStatus == rpc.TxnFinalityStatusAcceptedOnL1
+	case WaitForAcceptedOnL1:
+		return receipt.FinalityStatus == rpc.TxnFinalityStatusAcceptedOnL1
+	}
+	return false
+}
+
+// WaitForTransaction polls the transaction receipt until the transaction
+// reaches the requested status or ctx is done. It returns nil receipt when
+// options don't require waiting.
+func WaitForTransaction(
+	ctx context.Context,
+	client rpc.RpcProvider,
+	transactionHash *felt.Felt,
+	options *WaitOptions,
+) (*rpc.TransactionReceiptWithBlockInfo, error) {
+	if options.WaitFor == WaitForNone {
+		return nil, nil
+	}
+
+	ticker := time.NewTicker(options.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		receipt, err := client.TransactionReceipt(ctx, transactionHash)
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("stopped waiting for transaction %s: %w", transactionHash, ctx.Err())
+		}
+		if err != nil {
+			rpcErr, ok := err.(*rpc.RPCError)
+			if !ok || rpcErr.Code != rpc.ErrHashNotFound.Code {
+				return nil, err
+			}
+		} else if receiptReached(receipt, options.WaitFor) {
+			return receipt, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("stopped waiting for transaction %s: %w", transactionHash, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,224 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+
+	framework_types "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestParseWaitFor(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    WaitFor
+		wantErr bool
+	}{
+		{value: "none", want: WaitForNone},
+		{value: "pre_confirmed", want: WaitForPreConfirmed},
+		{value: "accepted_on_l2", want: WaitForAcceptedOnL2},
+		{value: "accepted_on_l1", want: WaitForAcceptedOnL1},
+		{value: "ACCEPTED_ON_L2", wantErr: true},
+		{value: "accepted-on-l2", wantErr: true},
+		{value: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parseWaitFor(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseWaitFor() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseWaitFor() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTransactionWaitModelWaitOptions(t *testing.T) {
+	defaults := WaitOptions{WaitFor: WaitForAcceptedOnL1, PollInterval: time.Second, RebroadcastAfter: time.Minute}
+
+	tests := []struct {
+		name    string
+		model   TransactionWaitModel
+		want    WaitOptions
+		wantErr bool
+	}{
+		{
+			name:  "provider defaults",
+			model: TransactionWaitModel{},
+			want:  defaults,
+		},
+		{
+			name: "resource settings",
+			model: TransactionWaitModel{
+				WaitFor:      framework_types.StringValue("none"),
+				PollInterval: framework_types.StringValue("250ms"),
+			},
+			want: WaitOptions{WaitFor: WaitForNone, PollInterval: 250 * time.Millisecond, RebroadcastAfter: time.Minute},
+		},
+		{
+			name:    "invalid wait_for",
+			model:   TransactionWaitModel{WaitFor: framework_types.StringValue("l2")},
+			wantErr: true,
+		},
+		{
+			name:    "zero poll_interval",
+			model:   TransactionWaitModel{PollInterval: framework_types.StringValue("0s")},
+			wantErr: true,
+		},
+		{
+			name:    "invalid poll_interval",
+			model:   TransactionWaitModel{PollInterval: framework_types.StringValue("5")},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, diags := tt.model.WaitOptions(defaults)
+			if diags.HasError() != tt.wantErr {
+				t.Fatalf("WaitOptions() = %v, wantErr %v", diags, tt.wantErr)
+			}
+			if !tt.wantErr && *got != tt.want {
+				t.Errorf("WaitOptions() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+// receiptSequence answers receipt requests with the given finality statuses
+// in order, an empty status meaning the node doesn't know the transaction
+// yet. The last answer repeats.
+func receiptSequence(calls *int, statuses ...rpc.TxnFinalityStatus) func(*felt.Felt) (*rpc.TransactionReceiptWithBlockInfo, error) {
+	return func(transactionHash *felt.Felt) (*rpc.TransactionReceiptWithBlockInfo, error) {
+		status := statuses[min(*calls, len(statuses)-1)]
+		*calls++
+		if status == "" {
+			return nil, rpc.ErrHashNotFound
+		}
+		return &rpc.TransactionReceiptWithBlockInfo{TransactionReceipt: rpc.TransactionReceipt{
+			TransactionHash: transactionHash,
+			FinalityStatus:  status,
+			ExecutionStatus: rpc.TxnExecutionStatusSUCCEEDED,
+		}}, nil
+	}
+}
+
+func TestWaitForTransaction(t *testing.T) {
+	tests := []struct {
+		name       string
+		waitFor    WaitFor
+		statuses   []rpc.TxnFinalityStatus
+		wantStatus rpc.TxnFinalityStatus
+		wantCalls  int
+	}{
+		{
+			// RECEIVED transactions have no receipt yet
+			name:       "received then accepted on l2",
+			waitFor:    WaitForAcceptedOnL2,
+			statuses:   []rpc.TxnFinalityStatus{"", "", rpc.TxnFinalityStatusAcceptedOnL2},
+			wantStatus: rpc.TxnFinalityStatusAcceptedOnL2,
+			wantCalls:  3,
+		},
+		{
+			name:       "accepted on l2 then l1",
+			waitFor:    WaitForAcceptedOnL1,
+			statuses:   []rpc.TxnFinalityStatus{rpc.TxnFinalityStatusAcceptedOnL2, rpc.TxnFinalityStatusAcceptedOnL1},
+			wantStatus: rpc.TxnFinalityStatusAcceptedOnL1,
+			wantCalls:  2,
+		},
+		{
+			name:       "pre-confirmed takes the first receipt",
+			waitFor:    WaitForPreConfirmed,
+			statuses:   []rpc.TxnFinalityStatus{"", rpc.TxnFinalityStatusAcceptedOnL2},
+			wantStatus: rpc.TxnFinalityStatusAcceptedOnL2,
+			wantCalls:  2,
+		},
+		{
+			name:      "none doesn't poll",
+			waitFor:   WaitForNone,
+			statuses:  []rpc.TxnFinalityStatus{""},
+			wantCalls: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			client := &fakeRpcProvider{transactionReceipt: receiptSequence(&calls, tt.statuses...)}
+
+			receipt, err := WaitForTransaction(
+				context.Background(),
+				client,
+				new(felt.Felt).SetUint64(0xabc),
+				&WaitOptions{WaitFor: tt.waitFor, PollInterval: time.Millisecond},
+			)
+			if err != nil {
+				t.Fatalf("WaitForTransaction() error = %v", err)
+			}
+			if tt.wantStatus == "" {
+				if receipt != nil {
+					t.Errorf("WaitForTransaction() receipt = %+v, want nil", receipt)
+				}
+			} else if receipt == nil || receipt.FinalityStatus != tt.wantStatus {
+				t.Errorf("WaitForTransaction() receipt = %+v, want status %s", receipt, tt.wantStatus)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("WaitForTransaction() made %d receipt calls, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestWaitForTransactionCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	calls := 0
+	client := &fakeRpcProvider{
+		transactionReceipt: func(*felt.Felt) (*rpc.TransactionReceiptWithBlockInfo, error) {
+			// Ctrl-C while the transaction is still RECEIVED
+			calls++
+			if calls == 2 {
+				cancel()
+			}
+			return nil, rpc.ErrHashNotFound
+		},
+	}
+
+	_, err := WaitForTransaction(
+		ctx,
+		client,
+		new(felt.Felt).SetUint64(0xabc),
+		&WaitOptions{WaitFor: WaitForAcceptedOnL2, PollInterval: time.Millisecond},
+	)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("WaitForTransaction() error = %v, want context.Canceled", err)
+	}
+	if calls != 2 {
+		t.Errorf("WaitForTransaction() made %d receipt calls after cancellation, want 2 in total", calls)
+	}
+}
+
+func TestWaitForTransactionNodeError(t *testing.T) {
+	client := &fakeRpcProvider{
+		transactionReceipt: func(*felt.Felt) (*rpc.TransactionReceiptWithBlockInfo, error) {
+			return nil, errors.New("connection refused")
+		},
+	}
+
+	_, err := WaitForTransaction(
+		context.Background(),
+		client,
+		new(felt.Felt).SetUint64(0xabc),
+		&WaitOptions{WaitFor: WaitForAcceptedOnL2, PollInterval: time.Millisecond},
+	)
+	if err == nil {
+		t.Fatal("WaitForTransaction() error = nil, want node error")
+	}
+}
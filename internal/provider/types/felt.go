@@ -50,7 +50,7 @@ func (f Felt) ToTerraformValue(ctx context.Context) (tftypes.Value, error) {
 
 	switch f.state {
 	case attr.ValueStateKnown:
-		return tftypes.NewValue(t, f.Felt), nil
+		return tftypes.NewValue(t, f.Felt.String()), nil
 	case attr.ValueStateNull:
 		return tftypes.NewValue(t, nil), nil
 	case attr.ValueStateUnknown:
@@ -89,5 +89,13 @@ func (f Felt) ToStringValue(ctx context.Context) (basetypes.StringValue, diag.Di
 
 func (f *Felt) FromFelt(v *felt.Felt) *Felt {
 	f.Felt = v
+	f.state = attr.ValueStateKnown
 	return f
 }
+
+// NewFeltNull creates a null Felt value.
+func NewFeltNull() Felt {
+	return Felt{
+		state: attr.ValueStateNull,
+	}
+}